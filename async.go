@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncOption configures asynchronous writing enabled by SetAsync.
+type AsyncOption func(*asyncConfig)
+
+// WithDropOldest makes a full buffer drop its oldest line to make room for a new one instead of blocking the caller.
+// Dropped lines are counted, see Logger.AsyncDropped.
+func WithDropOldest() AsyncOption {
+	return func(c *asyncConfig) {
+		c.dropOldest = true
+	}
+}
+
+// asyncConfig holds the asynchronous writing configuration, a zero bufferSize disables it.
+type asyncConfig struct {
+	bufferSize int
+	dropOldest bool
+}
+
+// asyncItem is a line to write or, if done is set, a flush request to acknowledge by closing done.
+type asyncItem struct {
+	line []byte
+	done chan struct{}
+}
+
+// asyncWriter hands writes over to a single goroutine writing them to out through a buffered channel, so that callers
+// don't wait for the file I/O.
+type asyncWriter struct {
+	out        io.Writer
+	dropOldest bool
+	items      chan asyncItem
+	stopped    chan struct{}
+	dropped    uint64
+
+	// mu guards closing items, writers hold it for reading while sending
+	mu     sync.RWMutex
+	closed bool
+}
+
+// newAsyncWriter creates an asyncWriter buffering up to bufferSize lines and starts its goroutine.
+func newAsyncWriter(out io.Writer, cfg asyncConfig) *asyncWriter {
+	w := &asyncWriter{
+		out:        out,
+		dropOldest: cfg.dropOldest,
+		items:      make(chan asyncItem, cfg.bufferSize),
+		stopped:    make(chan struct{}),
+	}
+	go w.run()
+
+	return w
+}
+
+// Write queues a copy of p to be written. Once the writer is stopped p is written synchronously.
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return w.out.Write(p)
+	}
+	// Nothing to write, e.g. for tagged entries which are written to their own file
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	line := make([]byte, len(p))
+	copy(line, p)
+	w.send(asyncItem{line: line})
+
+	return len(p), nil
+}
+
+// flush waits until the lines queued so far are written.
+func (w *asyncWriter) flush() {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return
+	}
+
+	// The request waits for room rather than dropping a line
+	done := make(chan struct{})
+	w.items <- asyncItem{done: done}
+	w.mu.RUnlock()
+
+	<-done
+}
+
+// stop writes the queued lines and stops the goroutine.
+func (w *asyncWriter) stop() {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.items)
+	}
+	w.mu.Unlock()
+
+	<-w.stopped
+}
+
+// send queues item, blocking while the buffer is full unless the oldest lines are dropped to make room. Callers must
+// hold w.mu for reading.
+func (w *asyncWriter) send(item asyncItem) {
+	if !w.dropOldest {
+		w.items <- item
+		return
+	}
+
+	for {
+		select {
+		case w.items <- item:
+			return
+		default:
+		}
+
+		select {
+		case oldest := <-w.items:
+			if oldest.done != nil {
+				// Flush requests are acknowledged rather than dropped
+				close(oldest.done)
+			} else {
+				atomic.AddUint64(&w.dropped, 1)
+			}
+		default:
+		}
+	}
+}
+
+// run writes the queued lines until the writer is stopped.
+func (w *asyncWriter) run() {
+	defer close(w.stopped)
+
+	for item := range w.items {
+		if item.done != nil {
+			close(item.done)
+			continue
+		}
+
+		// out reports its errors itself since there is no caller to return them to, see Logger.getWriter
+		_, _ = w.out.Write(item.line)
+	}
+}
@@ -0,0 +1,172 @@
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// blockingWriter blocks writes until release is closed.
+type blockingWriter struct {
+	syncBuffer
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return w.syncBuffer.Write(p)
+}
+
+func TestSetAsync(t *testing.T) {
+
+	var buf syncBuffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetAsync(16)
+	defer l.SetAsync(0)
+
+	for i := 0; i < 100; i++ {
+		l.Infof("message %d", i)
+	}
+
+	// Flush waits for the buffered lines
+	require.NoError(t, l.Flush())
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 100)
+	for i, line := range lines {
+		require.Contains(t, line, "message "+strconv.Itoa(i)+" ")
+	}
+	require.Zero(t, l.AsyncDropped())
+
+	// Disabling writes the pending lines and logs synchronously
+	l.Infof("%s", "pending")
+	l.SetAsync(0)
+	require.Contains(t, buf.String(), "pending")
+	l.Infof("%s", "synchronous")
+	require.Contains(t, buf.String(), "synchronous")
+}
+
+func TestSetAsyncDropOldest(t *testing.T) {
+
+	w := &blockingWriter{release: make(chan struct{})}
+	l := New(WithConsole(false))
+	l.SetOutput(w)
+	l.SetAsync(4, WithDropOldest())
+	defer l.SetAsync(0)
+
+	// The first line is taken by the writer goroutine which blocks, the next 4 fill the buffer
+	l.Infof("%s", "first")
+	require.Eventually(t, func() bool {
+		return len(l.async.items) == 0
+	}, time.Second, time.Millisecond)
+	for i := 0; i < 10; i++ {
+		l.Infof("message %d", i)
+	}
+	require.Equal(t, uint64(6), l.AsyncDropped())
+
+	close(w.release)
+	require.NoError(t, l.Flush())
+	lines := strings.Split(strings.TrimSpace(w.String()), "\n")
+	require.Len(t, lines, 5)
+	require.Contains(t, lines[0], "first")
+	for i, line := range lines[1:] {
+		require.Contains(t, line, "message "+strconv.Itoa(i+6)+" ")
+	}
+}
+
+func TestSetAsyncClose(t *testing.T) {
+
+	f, err := ioutil.TempFile("", "async-*.log")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	defer os.Remove(f.Name())
+
+	l := New(WithConsole(false), WithFilePath(f.Name()))
+	l.SetAsync(1024)
+	defer l.SetAsync(0)
+
+	for i := 0; i < 100; i++ {
+		l.Infof("message %d", i)
+	}
+	require.NoError(t, l.Close())
+
+	data, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 100)
+}
+
+func TestSetAsyncWriteError(t *testing.T) {
+
+	l := New(WithConsole(false))
+	l.SetOutput(failingWriter{})
+	l.SetAsync(16)
+	defer l.SetAsync(0)
+
+	var mu sync.Mutex
+	var errs []string
+	l.SetInternalErrorHandler(func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs = append(errs, err.Error())
+	})
+
+	// The errors of the lines written by the goroutine are reported like those of synchronous writes
+	l.Infof("unwritten")
+	require.NoError(t, l.Flush())
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"failed to write to log: disk full"}, errs)
+}
+
+// benchmarkLatency logs to a temporary log file from parallel goroutines and reports the p99 latency of the calls.
+func benchmarkLatency(b *testing.B, async bool) {
+	f, err := ioutil.TempFile("", "bench-*.log")
+	require.NoError(b, err)
+	require.NoError(b, f.Close())
+	defer os.Remove(f.Name())
+
+	l := New(WithConsole(false), WithFilePath(f.Name()), WithRotationConfig(RotationConfig{MaxSize: 1024}))
+	if async {
+		l.SetAsync(1 << 16)
+		defer l.SetAsync(0)
+	}
+	defer l.Close()
+
+	var mu sync.Mutex
+	latencies := make([]time.Duration, 0, b.N)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		local := make([]time.Duration, 0, 1024)
+		for pb.Next() {
+			start := time.Now()
+			l.Infof("%s", "benchmark message")
+			local = append(local, time.Since(start))
+		}
+
+		mu.Lock()
+		latencies = append(latencies, local...)
+		mu.Unlock()
+	})
+	b.StopTimer()
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	if len(latencies) > 0 {
+		b.ReportMetric(float64(latencies[len(latencies)*99/100].Nanoseconds()), "p99-ns")
+	}
+}
+
+func BenchmarkInfofSync(b *testing.B) {
+	benchmarkLatency(b, false)
+}
+
+func BenchmarkInfofAsync(b *testing.B) {
+	benchmarkLatency(b, true)
+}
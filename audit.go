@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// auditTag is the tag of the audit lines and names their file, e.g. app.audit.log
+const auditTag tagName = "audit"
+
+// auditKeys are the keys of the mandatory audit fields which the extra fields can't override
+var auditKeys = map[string]bool{
+	"actor":     true,
+	"action":    true,
+	"resource":  true,
+	"outcome":   true,
+	tagKey:      true,
+	prevHashKey: true,
+	hashKey:     true,
+}
+
+// Audit writes an audit line recording that actor performed action on resource with outcome, e.g. Audit("alice",
+// "delete", "invoice/42", "success", nil), to the audit file next to the log file, e.g. app.audit.log for app.log. The
+// audit file is kept apart from the operational log: the lines don't reach the log file, the console or hooks, and the
+// level, filters and sampling don't apply. Each line is a JSON object regardless of the format, at level Info with the
+// tag field set to audit, the caller and the extra fields. Extra fields named like a mandatory or reserved field are
+// prefixed with "fields.". The file is rotated like the log file and closed by Close, EnableHashChain links its lines
+// by their hashes. It returns an error if one of the mandatory arguments is empty or the line can't be written.
+func (l *Logger) Audit(actor, action, resource, outcome string, extra map[string]interface{}) error {
+	return l.audit(actor, action, resource, outcome, extra)
+}
+
+// audit writes an audit line. Exported helpers must call it directly so that the caller frame is always
+// skipFrameCount-1 frames away, one less than for the other helpers since there's no newEntry in between.
+func (l *Logger) audit(actor, action, resource, outcome string, extra map[string]interface{}) error {
+	if actor == "" || action == "" || resource == "" || outcome == "" {
+		return errors.New("audit actor, action, resource and outcome must not be empty")
+	}
+
+	entry := l.log.WithFields(logrus.Fields{})
+	for key, value := range extra {
+		if reservedKeys[key] || auditKeys[key] {
+			key = "fields." + key
+		}
+		entry.Data[key] = value
+	}
+	entry.Data["actor"] = actor
+	entry.Data["action"] = action
+	entry.Data["resource"] = resource
+	entry.Data["outcome"] = outcome
+	entry.Data[tagKey] = auditTag
+
+	if atomic.LoadInt32(&l.noCaller) == 0 {
+		skip := skipFrameCount - 1 + int(atomic.LoadInt32(&l.callerSkip))
+		prefixes, _ := l.trimPrefixes.Load().([]string)
+		short := atomic.LoadInt32(&l.functionStyle) == int32(FunctionShort)
+		file, function, line := callerInfo(skip, splitAfterPkgName, prefixes, short)
+		entry.Data["file"] = file
+		entry.Data["line"] = line
+		entry.Data["function"] = function
+	}
+
+	entry.Time = time.Now()
+	entry.Level = logrus.InfoLevel
+	entry.Message = action + " " + resource + " by " + actor + ": " + outcome
+
+	line, err := l.formatter.formatAs(entry, FormatJSON)
+	if err != nil {
+		return err
+	}
+
+	file, path, chain, err := l.auditFile()
+	if err != nil {
+		return err
+	}
+	if chain != nil {
+		return chain.write(file, path, line)
+	}
+
+	_, err = file.Write(line)
+	return err
+}
+
+// auditFile returns the audit file, creating it next to the log file on first use, the path of the file currently
+// written and the hash chain if it's enabled.
+func (l *Logger) auditFile() (io.Writer, string, *hashChain, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.audits == nil {
+		path := tagFilePath(l.filePath, string(auditTag))
+		file, err := openSideFile(path, l.rotation, l.daily)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		l.audits = file
+		l.auditPath = path
+	}
+
+	path := l.auditPath
+	if l.daily {
+		path = dailyFileName(path, time.Now())
+	}
+
+	return l.audits, path, l.chain, nil
+}
+
+// Audit writes an audit line of the default logger to its audit file. See Logger.Audit.
+func Audit(actor, action, resource, outcome string, extra map[string]interface{}) error {
+	return std.audit(actor, action, resource, outcome, extra)
+}
@@ -0,0 +1,71 @@
+package logger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAudit(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_audit_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	l := New(WithConsole(false), WithFilePath(path), WithLevel(logrus.ErrorLevel))
+	defer l.Close()
+
+	// Audit lines are written regardless of the level
+	require.NoError(t, l.Audit("alice", "delete", "invoice/42", "success",
+		map[string]interface{}{"ip": "10.0.0.1", "actor": "mallory"}))
+	require.EqualError(t, l.Audit("alice", "", "invoice/42", "success", nil),
+		"audit actor, action, resource and outcome must not be empty")
+	l.Errorf("%s", "operational")
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "app.audit.log"))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	require.Len(t, lines, 1)
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &line))
+	require.Equal(t, "INFO", line["level"])
+	require.Equal(t, "audit", line["tag"])
+	require.Equal(t, "delete invoice/42 by alice: success", line["message"])
+	require.Equal(t, "alice", line["actor"])
+	require.Equal(t, "delete", line["action"])
+	require.Equal(t, "invoice/42", line["resource"])
+	require.Equal(t, "success", line["outcome"])
+	require.Equal(t, "10.0.0.1", line["ip"])
+	require.Equal(t, "mallory", line["fields.actor"])
+	require.Equal(t, "audit_test.go", line["file"])
+
+	// The log file only has the operational lines
+	content, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "operational")
+	require.NotContains(t, string(content), "invoice/42")
+}
+
+func TestAuditDefaultLogger(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_audit_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer ResetForTest()
+	require.NoError(t, SetLogFilePath(filepath.Join(dir, "app.log")))
+	require.NoError(t, Audit("alice", "login", "session", "failure", nil))
+	require.NoError(t, Close())
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "app.audit.log"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"function":".TestAuditDefaultLogger"`)
+}
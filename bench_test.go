@@ -0,0 +1,146 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"testing"
+)
+
+// newBenchLogger returns a logger at Info level discarding its lines, without console or file.
+func newBenchLogger(opts ...Option) *Logger {
+	l := New(append([]Option{WithConsole(false), WithFileLogging(false)}, opts...)...)
+	l.SetOutput(ioutil.Discard)
+
+	return l
+}
+
+// requestFields are the fields repeated on every line of a request.
+var requestFields = map[string]interface{}{"request_id": "42", "user": "alice", "method": "GET", "path": "/v1/items"}
+
+func BenchmarkDebugfDisabled(b *testing.B) {
+	l := newBenchLogger()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debugf("request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkDebugfDisabledEntry(b *testing.B) {
+	l := newBenchLogger()
+	entry := l.With(map[string]interface{}{"request_id": "abc-123"})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entry.Debugf("request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkDebugfDisabledCtx(b *testing.B) {
+	l := newBenchLogger()
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{"request_id": "abc-123"})
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.DebugfCtx(ctx, "request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkDebugwDisabled(b *testing.B) {
+	l := newBenchLogger()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Debugw("request", "path", "GET /v1/items", "ms", 12)
+	}
+}
+
+func BenchmarkDebugfDisabledDefaultLogger(b *testing.B) {
+	defer ResetForTest()
+	SetOutput(ioutil.Discard)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Debugf("request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkInfofText(b *testing.B) {
+	l := newBenchLogger()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Infof("request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkInfofJSON(b *testing.B) {
+	l := newBenchLogger(WithFormat(FormatJSON))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Infof("request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkInfofTextFields(b *testing.B) {
+	l := newBenchLogger()
+	entry := l.With(requestFields)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entry.Infof("request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkInfofJSONFields(b *testing.B) {
+	l := newBenchLogger(WithFormat(FormatJSON))
+	entry := l.With(requestFields)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entry.Infof("request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkInfofWithFields(b *testing.B) {
+	l := newBenchLogger()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.WithFields(requestFields).Infof("request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkInfofWith(b *testing.B) {
+	l := newBenchLogger()
+	entry := l.With(requestFields)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entry.Infof("request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkInfofTextNoCaller(b *testing.B) {
+	l := newBenchLogger()
+	l.SetReportCaller(false)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Infof("request %s took %d ms", "GET /v1/items", 12)
+	}
+}
+
+func BenchmarkLogErrorDisabled(b *testing.B) {
+	l := newBenchLogger()
+	l.SetEnabled(false)
+	err := fmt.Errorf("save failed: %w", errors.New("disk full"))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = l.LogError(err, "request %s failed", "GET /v1/items")
+	}
+}
@@ -0,0 +1,145 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// burstQuiet is how long a call site has to stay silent for its burst to end
+const burstQuiet = time.Second
+
+// burstSampler keeps the first messages of a burst per key and every nth one after them, logging a summary of the
+// dropped messages when the burst ends.
+type burstSampler struct {
+	first int
+	every int
+	quiet time.Duration
+	log   func(entry *logrus.Entry, level logrus.Level, msg string)
+
+	mu    sync.Mutex
+	sites map[string]*burst
+}
+
+// burst is the state of the current burst of a key.
+type burst struct {
+	count int
+	// dropped counts the messages dropped since the last one kept
+	dropped int
+	// last and level are the entry and level of the last dropped message, used by the summary
+	last  *logrus.Entry
+	level logrus.Level
+	timer *time.Timer
+}
+
+// newBurstSampler creates a burstSampler keeping the first messages and every nth one thereafter of each burst,
+// writing the summaries with log.
+func newBurstSampler(first, every int, quiet time.Duration,
+	log func(entry *logrus.Entry, level logrus.Level, msg string)) *burstSampler {
+	return &burstSampler{
+		first: first,
+		every: every,
+		quiet: quiet,
+		log:   log,
+		sites: make(map[string]*burst),
+	}
+}
+
+// allow reports whether entry at level with key is kept. A kept entry after dropped ones reports their number.
+func (s *burstSampler) allow(key string, entry *logrus.Entry, level logrus.Level) (allowed bool, dropped int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.sites[key]
+	if !ok {
+		b = &burst{}
+		s.sites[key] = b
+		b.timer = time.AfterFunc(s.quiet, func() {
+			s.end(key, b)
+		})
+	} else {
+		b.timer.Reset(s.quiet)
+	}
+
+	b.count++
+	if b.count <= s.first || (b.count-s.first)%s.every == 0 {
+		dropped = b.dropped
+		b.dropped = 0
+		return true, dropped
+	}
+
+	b.dropped++
+	b.last = entry
+	b.level = level
+
+	return false, 0
+}
+
+// end ends the burst b of key once it's quiet, unless it was ended by flush already.
+func (s *burstSampler) end(key string, b *burst) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sites[key] == b {
+		delete(s.sites, key)
+		s.writeSummary(b)
+	}
+}
+
+// flush ends all bursts, writing out the summaries of their dropped messages.
+func (s *burstSampler) flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, b := range s.sites {
+		b.timer.Stop()
+		delete(s.sites, key)
+		s.writeSummary(b)
+	}
+}
+
+// writeSummary logs the number of messages of b dropped since the last one kept with the level and caller of the last
+// dropped one, callers must hold s.mu.
+func (s *burstSampler) writeSummary(b *burst) {
+	if b.dropped == 0 {
+		return
+	}
+
+	b.last.Data["dropped"] = b.dropped
+	s.log(b.last, b.level, fmt.Sprintf("%d messages dropped by burst sampling", b.dropped))
+}
+
+// SetBurstSampling keeps the first messages of a burst from each call site, identified by the caller file and line,
+// and then every thereafterEvery-th one, e.g. SetBurstSampling(10, 100) logs the first 10 errors of a burst and then
+// every 100th, so that an incident shows up immediately without flooding the log. A kept message following dropped
+// ones carries their count in the dropped field. A burst ends once its call site stays silent for a second, the count
+// of the messages dropped after the last kept one is then logged as a summary with the level and caller of the last
+// dropped message, which Flush, Close and changing the level do too. Fatal and panic messages are never dropped. Burst
+// sampling is disabled by default, first <= 0 or thereafterEvery <= 0 disables it. It can be combined with SetSampling,
+// which is applied first.
+func (l *Logger) SetBurstSampling(first, thereafterEvery int) {
+	var s *burstSampler
+	if first > 0 && thereafterEvery > 0 {
+		s = newBurstSampler(first, thereafterEvery, burstQuiet, l.logEntry)
+	}
+
+	if old, _ := l.burst.Load().(*burstSampler); old != nil {
+		old.flush()
+	}
+	l.burst.Store(s)
+}
+
+// flushBurst writes out the summaries of the bursts sampled by SetBurstSampling.
+func (l *Logger) flushBurst() {
+	if s, _ := l.burst.Load().(*burstSampler); s != nil {
+		s.flush()
+	}
+}
+
+// SetBurstSampling keeps the first messages of a burst from each call site of the default logger and every
+// thereafterEvery-th one after them. See Logger.SetBurstSampling.
+func SetBurstSampling(first, thereafterEvery int) {
+	std.SetBurstSampling(first, thereafterEvery)
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBurstSamplerAllow(t *testing.T) {
+
+	var summaries []string
+	s := newBurstSampler(2, 3, time.Hour, func(entry *logrus.Entry, level logrus.Level, msg string) {
+		require.Equal(t, logrus.WarnLevel, level)
+		summaries = append(summaries, msg)
+	})
+
+	var kept []int
+	for i := 1; i <= 10; i++ {
+		allowed, dropped := s.allow("main.go:10", logrus.NewEntry(logrus.New()), logrus.WarnLevel)
+		if allowed {
+			kept = append(kept, i)
+		}
+		if i == 5 || i == 8 {
+			require.Equal(t, 2, dropped)
+		}
+	}
+	// The first two and every third after them
+	require.Equal(t, []int{1, 2, 5, 8}, kept)
+
+	// Other call sites have their own bursts
+	allowed, _ := s.allow("main.go:20", logrus.NewEntry(logrus.New()), logrus.WarnLevel)
+	require.True(t, allowed)
+
+	s.flush()
+	require.Equal(t, []string{"2 messages dropped by burst sampling"}, summaries)
+
+	// A new burst starts over
+	allowed, dropped := s.allow("main.go:10", logrus.NewEntry(logrus.New()), logrus.WarnLevel)
+	require.True(t, allowed)
+	require.Zero(t, dropped)
+}
+
+func TestSetBurstSampling(t *testing.T) {
+
+	var buf syncBuffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetBurstSampling(1, 10)
+
+	retry := func() {
+		l.Errorf("%s", "retrying")
+	}
+
+	for i := 0; i < 15; i++ {
+		retry()
+	}
+	// The 1st and 11th are kept, the 11th carries the count of the 9 dropped in between
+	require.Equal(t, 2, strings.Count(buf.String(), "retrying"))
+	require.Contains(t, buf.String(), "dropped=9")
+
+	// Changing the level writes out the summary of the 4 dropped after the 11th with their level and caller
+	buf.Reset()
+	l.SetLevelL(logrus.WarnLevel)
+	require.Contains(t, buf.String(), "ERROR ")
+	require.Contains(t, buf.String(), "4 messages dropped by burst sampling")
+	require.Contains(t, buf.String(), "dropped=4")
+	require.Contains(t, buf.String(), "func:.TestSetBurstSampling.func1")
+
+	// The burst ends once the call site is quiet
+	buf.Reset()
+	for i := 0; i < 3; i++ {
+		retry()
+	}
+	require.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	require.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "2 messages dropped by burst sampling")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Fatal messages are never dropped
+	buf.Reset()
+	l.SetExitFunc(func(int) {})
+	for i := 0; i < 3; i++ {
+		l.Fatalf("%s", "fatal")
+	}
+	require.Equal(t, 3, strings.Count(buf.String(), "fatal"))
+
+	l.SetBurstSampling(0, 0)
+	buf.Reset()
+	for i := 0; i < 3; i++ {
+		retry()
+	}
+	require.Equal(t, 3, strings.Count(buf.String(), "retrying"))
+}
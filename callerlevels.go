@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetCallerLevels restricts the caller fields to the lines logged at levels, e.g. WarnLevel and ErrorLevel, so that the
+// caller isn't resolved for the bulk of Info and Debug lines. The caller is still resolved at other levels if sampling
+// or the stack trace need it, without being reported. Without levels the caller is reported at all levels, the
+// default. SetReportCaller(false) disables it at all levels regardless.
+func (l *Logger) SetCallerLevels(levels ...logrus.Level) {
+	var skipped uint32
+	if len(levels) > 0 {
+		skipped = ^uint32(0)
+		for _, level := range levels {
+			skipped &^= 1 << level
+		}
+	}
+
+	atomic.StoreUint32(&l.noCallerLevels, skipped)
+}
+
+// callerLevel reports whether the caller is reported at level.
+func (l *Logger) callerLevel(level logrus.Level) bool {
+	return atomic.LoadUint32(&l.noCallerLevels)&(1<<level) == 0
+}
+
+// SetCallerLevels restricts the caller fields of the default logger to the lines logged at levels. See
+// Logger.SetCallerLevels.
+func SetCallerLevels(levels ...logrus.Level) {
+	std.SetCallerLevels(levels...)
+}
@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCallerLevels(t *testing.T) {
+
+	l := New(WithConsole(false))
+	logs, restore := l.CaptureForTest()
+	defer restore()
+
+	l.SetCallerLevels(logrus.WarnLevel, logrus.ErrorLevel)
+	l.Infof("info")
+	l.Warnf("warn")
+	l.Errorf("error")
+
+	entries := logs.Entries()
+	require.NotContains(t, entries[0].Fields, "file")
+	require.NotContains(t, entries[0].Fields, "function")
+	require.Contains(t, entries[1].Fields["file"], "callerlevels_test.go")
+	require.Contains(t, entries[2].Fields["file"], "callerlevels_test.go")
+
+	// Without levels the caller is reported at all levels again
+	l.SetCallerLevels()
+	l.Infof("info")
+	require.Contains(t, logs.Last().Fields["file"], "callerlevels_test.go")
+}
+
+func BenchmarkInfofCallerLevels(b *testing.B) {
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+	l.SetCallerLevels(logrus.WarnLevel, logrus.ErrorLevel)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Infof("%s", "caller levels")
+	}
+}
@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"io/ioutil"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CapturedEntry is a log entry recorded by CaptureForTest.
+type CapturedEntry struct {
+	Level   logrus.Level
+	Message string
+	// Fields holds the custom fields and the caller fields file, line and function
+	Fields map[string]interface{}
+}
+
+// CapturedLogs holds the entries recorded by CaptureForTest. It's safe for concurrent use.
+type CapturedLogs struct {
+	mu      sync.Mutex
+	entries []CapturedEntry
+}
+
+// Entries returns a copy of the recorded entries in logging order.
+func (c *CapturedLogs) Entries() []CapturedEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries := make([]CapturedEntry, len(c.entries))
+	copy(entries, c.entries)
+
+	return entries
+}
+
+// Last returns the last recorded entry, or the zero CapturedEntry if nothing was logged.
+func (c *CapturedLogs) Last() CapturedEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) == 0 {
+		return CapturedEntry{}
+	}
+
+	return c.entries[len(c.entries)-1]
+}
+
+// Len returns the number of recorded entries.
+func (c *CapturedLogs) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+// Reset discards the recorded entries.
+func (c *CapturedLogs) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = nil
+}
+
+// captureHook records entries into logs.
+type captureHook struct {
+	logs *CapturedLogs
+}
+
+// Levels returns the levels the hook fires for.
+func (h *captureHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire records entry.
+func (h *captureHook) Fire(entry *logrus.Entry) error {
+	fields := make(map[string]interface{}, len(entry.Data))
+	for key, value := range entry.Data {
+		fields[key] = value
+	}
+
+	h.logs.mu.Lock()
+	defer h.logs.mu.Unlock()
+
+	h.logs.entries = append(h.logs.entries, CapturedEntry{
+		Level:   entry.Level,
+		Message: entry.Message,
+		Fields:  fields,
+	})
+
+	return nil
+}
+
+// CaptureForTest records the entries logged through l in memory instead of writing them to the output, so that tests
+// can assert on them without parsing log lines. The returned function restores the previous output and must be called
+// when the test is done, e.g. with defer.
+func (l *Logger) CaptureForTest() (*CapturedLogs, func()) {
+	logs := &CapturedLogs{}
+	hook := &captureHook{logs: logs}
+
+	l.mu.Lock()
+	output, console := l.output, l.console
+	l.output = ioutil.Discard
+	l.log.SetOutput(l.getWriter())
+	l.mu.Unlock()
+
+	l.AddHook(hook)
+
+	restore := func() {
+		l.removeHook(hook)
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		l.output, l.console = output, console
+		l.log.SetOutput(l.getWriter())
+	}
+
+	return logs, restore
+}
+
+// removeHook removes hook from all levels.
+func (l *Logger) removeHook(hook logrus.Hook) {
+	l.hooksMu.Lock()
+	defer l.hooksMu.Unlock()
+
+	hooks := make(logrus.LevelHooks)
+	for level, levelHooks := range l.log.Hooks {
+		for _, h := range levelHooks {
+			if unwrapHook(h) != hook {
+				hooks[level] = append(hooks[level], h)
+			}
+		}
+	}
+
+	l.log.ReplaceHooks(hooks)
+}
+
+// CaptureForTest records the entries logged through the default logger in memory. See Logger.CaptureForTest.
+func CaptureForTest() (*CapturedLogs, func()) {
+	return std.CaptureForTest()
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureForTest(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	captured, restore := l.CaptureForTest()
+
+	message := randStringBytes(30)
+	l.Infof("%s", "started")
+	l.WithFields(map[string]interface{}{"id": 7}).Errorf("%s", message)
+
+	require.Equal(t, 2, captured.Len())
+	require.Equal(t, logrus.ErrorLevel, captured.Last().Level)
+	require.Equal(t, message, captured.Last().Message)
+	require.Equal(t, 7, captured.Last().Fields["id"])
+	require.Contains(t, captured.Last().Fields["file"], "capture_test.go")
+	require.Equal(t, "started", captured.Entries()[0].Message)
+
+	// Nothing is written to the output while capturing
+	require.Empty(t, buf.String())
+
+	restore()
+
+	l.Infof("%s", message)
+	require.Equal(t, 2, captured.Len())
+	require.Contains(t, buf.String(), message)
+
+	captured.Reset()
+	require.Zero(t, captured.Len())
+	require.Equal(t, CapturedEntry{}, captured.Last())
+}
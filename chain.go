@@ -0,0 +1,220 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+const (
+	// prevHashKey is the field of an audit line holding the hash of the previous line
+	prevHashKey = "prev_hash"
+	// hashKey is the field of an audit line holding its own hash, always the last one
+	hashKey = "hash"
+)
+
+// hashChain links the audit lines by the hash of their predecessor.
+type hashChain struct {
+	mu     sync.Mutex
+	last   string
+	loaded bool
+}
+
+// EnableHashChain links the audit lines written by Audit by their hashes. Each line carries the hash of the previous
+// line in the prev_hash field and its own hash in the hash field, which is the hex encoded SHA-256 of the previous
+// hash followed by the line without the hash field, so that VerifyChain detects a line modified, inserted or deleted
+// between other lines without the following hashes being recomputed. It doesn't detect lines removed from the start
+// or the end of the files, and since the hash isn't keyed, anyone who can write the files can rebuild the chain after
+// tampering with it. Detecting those requires keeping the last hash elsewhere, e.g. in a separate system, and
+// comparing it with the file's. The chain continues from the last line of an existing audit file, e.g. after a restart,
+// and across rotations: the first line of a new file links to the last line of the rotated one, so the files must be
+// verified together in order. The chain starts over with an empty prev_hash if the audit file is missing or its last
+// line isn't chained.
+func (l *Logger) EnableHashChain() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.chain == nil {
+		l.chain = &hashChain{}
+	}
+}
+
+// write writes line chained to the previous line to file, loading the last hash from the file at path first if it's
+// the first line since the chain was enabled.
+func (c *hashChain) write(file io.Writer, path string, line []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.loaded {
+		last, err := lastHash(path)
+		if err != nil {
+			return err
+		}
+		c.last = last
+		c.loaded = true
+	}
+
+	line, hash, err := chainLine(line, c.last)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(line); err != nil {
+		return err
+	}
+	c.last = hash
+
+	return nil
+}
+
+// chainLine returns the JSON object line, which must not have the prev_hash and hash fields yet, with prev added as
+// the prev_hash field and the hash of the result appended as the hash field, and that hash.
+func chainLine(line []byte, prev string) ([]byte, string, error) {
+	line = bytes.TrimRight(line, "\r\n")
+	if !bytes.HasSuffix(line, []byte("}")) {
+		return nil, "", errors.New("audit line is not a JSON object")
+	}
+
+	prevField, err := json.Marshal(prev)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var body bytes.Buffer
+	body.Write(line[:len(line)-1])
+	body.WriteString(`,"` + prevHashKey + `":`)
+	body.Write(prevField)
+	body.WriteString("}")
+
+	hash := chainHash(prev, body.Bytes())
+
+	chained := body.Bytes()
+	chained = append(chained[:len(chained)-1], `,"`+hashKey+`":"`+hash+`"}`+"\n"...)
+
+	return chained, hash, nil
+}
+
+// chainHash returns the hex encoded SHA-256 of prev followed by body.
+func chainHash(prev string, body []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(prev))
+	sum.Write(body)
+
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// lastHash returns the hash of the last line of the file at path, empty if the file doesn't exist or its last line
+// isn't chained.
+func lastHash(path string) (string, error) {
+	line, err := lastLine(path)
+	if err != nil || line == nil {
+		return "", err
+	}
+
+	var fields map[string]interface{}
+	if json.Unmarshal(line, &fields) != nil {
+		return "", nil
+	}
+	hash, _ := fields[hashKey].(string)
+
+	return hash, nil
+}
+
+// lastLine returns the last non-empty line of the file at path, reading it backwards from the end so that large files
+// aren't read completely. It returns nil if the file doesn't exist or is empty.
+func lastLine(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	size := info.Size()
+	for n := int64(4096); ; n *= 2 {
+		if n > size {
+			n = size
+		}
+
+		buf := make([]byte, n)
+		if _, err := f.ReadAt(buf, size-n); err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		buf = bytes.TrimRight(buf, "\r\n")
+		if i := bytes.LastIndexByte(buf, '\n'); i >= 0 {
+			return buf[i+1:], nil
+		}
+		if n == size {
+			if len(buf) == 0 {
+				return nil, nil
+			}
+			return buf, nil
+		}
+	}
+}
+
+// VerifyChain verifies the hash chain of the audit lines read from r, written with EnableHashChain. The lines of
+// rotated files have to be read in the order they were written, e.g. by concatenating the files from the oldest to the
+// current one. The prev_hash of the first line isn't checked since it links to lines which may have been removed by
+// the rotation. It returns an error naming the first line which is not chained, e.g. because it was modified, or
+// whose predecessor was modified, inserted or removed.
+func VerifyChain(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+
+	var prev string
+	number := 0
+	for scanner.Scan() {
+		line := bytes.TrimRight(scanner.Bytes(), "\r")
+		if len(line) == 0 {
+			continue
+		}
+		number++
+
+		var fields struct {
+			PrevHash *string `json:"prev_hash"`
+			Hash     string  `json:"hash"`
+		}
+		if err := json.Unmarshal(line, &fields); err != nil {
+			return fmt.Errorf("audit line %d is not valid JSON: %v", number, err)
+		}
+		if fields.PrevHash == nil || fields.Hash == "" {
+			return fmt.Errorf("audit line %d is not chained", number)
+		}
+		if number > 1 && *fields.PrevHash != prev {
+			return fmt.Errorf("audit line %d doesn't follow the previous line", number)
+		}
+
+		suffix := []byte(`,"` + hashKey + `":"` + fields.Hash + `"}`)
+		if !bytes.HasSuffix(line, suffix) {
+			return fmt.Errorf("audit line %d doesn't end with its hash", number)
+		}
+		body := append(line[:len(line)-len(suffix):len(line)-len(suffix)], '}')
+		if chainHash(*fields.PrevHash, body) != fields.Hash {
+			return fmt.Errorf("audit line %d doesn't match its hash", number)
+		}
+
+		prev = fields.Hash
+	}
+
+	return scanner.Err()
+}
+
+// EnableHashChain links the audit lines of the default logger by their hashes. See Logger.EnableHashChain.
+func EnableHashChain() {
+	std.EnableHashChain()
+}
@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableHashChain(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_chain_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	auditPath := filepath.Join(dir, "app.audit.log")
+
+	l := New(WithConsole(false), WithFilePath(path))
+	l.EnableHashChain()
+	for _, outcome := range []string{"success", "failure", "success"} {
+		require.NoError(t, l.Audit("alice", "login", "session", outcome, nil))
+	}
+	require.NoError(t, l.Close())
+
+	// A restarted logger continues the chain of the file
+	l = New(WithConsole(false), WithFilePath(path))
+	l.EnableHashChain()
+	require.NoError(t, l.Audit("bob", "delete", "invoice/42", "success", nil))
+	require.NoError(t, l.Close())
+
+	content, err := ioutil.ReadFile(auditPath)
+	require.NoError(t, err)
+	require.NoError(t, VerifyChain(bytes.NewReader(content)))
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	require.Len(t, lines, 4)
+	var first, last map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[3]), &last))
+	require.Equal(t, "", first["prev_hash"])
+	require.Len(t, last["hash"], 64)
+	require.True(t, strings.HasSuffix(lines[3], `,"hash":"`+last["hash"].(string)+`"}`))
+
+	// Modified lines break the chain
+	modified := strings.Replace(string(content), `"outcome":"failure"`, `"outcome":"success"`, 1)
+	require.EqualError(t, VerifyChain(strings.NewReader(modified)), "audit line 2 doesn't match its hash")
+
+	// So do removed lines
+	removed := strings.Join(append([]string{lines[0]}, lines[2:]...), "\n")
+	require.EqualError(t, VerifyChain(strings.NewReader(removed)), "audit line 2 doesn't follow the previous line")
+
+	// And unchained lines
+	require.EqualError(t, VerifyChain(strings.NewReader(`{"level":"INFO"}`)), "audit line 1 is not chained")
+
+	// The first line of a rotated file links to the previous file
+	require.NoError(t, VerifyChain(strings.NewReader(strings.Join(lines[1:], "\n"))))
+}
+
+func TestLastLine(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_chain_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.audit.log")
+	line, err := lastLine(path)
+	require.NoError(t, err)
+	require.Nil(t, line)
+
+	// Lines longer than the first chunk read from the end
+	long := strings.Repeat("x", 10000)
+	require.NoError(t, ioutil.WriteFile(path, []byte("first\n"+long+"\n\n"), 0644))
+	line, err = lastLine(path)
+	require.NoError(t, err)
+	require.Equal(t, long, string(line))
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(long), 0644))
+	line, err = lastLine(path)
+	require.NoError(t, err)
+	require.Equal(t, long, string(line))
+}
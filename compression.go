@@ -0,0 +1,294 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Compression is the algorithm compressing rotated log files if RotationConfig.Compress is enabled.
+type Compression int
+
+const (
+	// CompressionGzip compresses rotated files with gzip, the default done by lumberjack itself.
+	CompressionGzip Compression = iota
+	// CompressionZstd compresses rotated files with zstd. It's only available when built with the zstd tag so that
+	// the dependency is compiled in only if needed.
+	CompressionZstd
+)
+
+const (
+	// backupTimeFormat is the layout of the timestamp in the names of the files rotated by lumberjack
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+
+	// megabyte is the unit of RotationConfig.MaxSize
+	megabyte = 1024 * 1024
+
+	// defaultMaxSize is the lumberjack's maximum size of the log file in megabytes if RotationConfig.MaxSize is zero
+	defaultMaxSize = 100
+)
+
+// compressor compresses rotated files with an algorithm lumberjack doesn't support.
+type compressor struct {
+	// ext is appended to the names of the compressed files
+	ext string
+	// newWriter returns a writer compressing to w
+	newWriter func(w io.Writer) (io.WriteCloser, error)
+}
+
+// compressors holds the algorithms available in addition to gzip, registered by the files built with their tag.
+var compressors = map[Compression]compressor{}
+
+// String returns the name of the algorithm.
+func (c Compression) String() string {
+	switch c {
+	case CompressionGzip:
+		return "gzip"
+	case CompressionZstd:
+		return "zstd"
+	default:
+		return fmt.Sprintf("Compression(%d)", int(c))
+	}
+}
+
+// SetCompression sets the algorithm compressing rotated log files if RotationConfig.Compress is enabled, gzip by
+// default. Algorithms other than gzip are applied after lumberjack rotated the file, the active file is never
+// compressed so that it can still be tailed. It returns an error if algo isn't available in this build, e.g.
+// CompressionZstd without the zstd build tag.
+func (l *Logger) SetCompression(algo Compression) error {
+	if _, ok := compressors[algo]; !ok && algo != CompressionGzip {
+		return fmt.Errorf("compression %s is not available, zstd requires building with the zstd tag", algo)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.compression = algo
+	l.log.SetOutput(l.getWriter())
+
+	return nil
+}
+
+// backupCompressor compresses the files rotated by lumberjack in the background and removes the compressed files
+// exceeding MaxBackups or MaxAge, which lumberjack doesn't recognize.
+type backupCompressor struct {
+	compressor
+	filename string
+	rotation RotationConfig
+
+	// size estimates the size of the active file to detect rotations, only accessed by Write
+	size int64
+
+	wake    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	stopped sync.WaitGroup
+}
+
+// newBackupCompressor returns a backupCompressor of the files rotated from filename, compressing the files left by a
+// previous run right away.
+func newBackupCompressor(c compressor, filename string, rotation RotationConfig) *backupCompressor {
+	b := &backupCompressor{
+		compressor: c,
+		filename:   filename,
+		rotation:   rotation,
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	if info, err := os.Stat(filename); err == nil {
+		b.size = info.Size()
+	}
+
+	b.stopped.Add(1)
+	go b.run()
+	b.notify()
+
+	return b
+}
+
+// written records a write of n bytes to the active file, waking the compressor if the file is likely rotated. False
+// positives only cost a directory scan.
+func (b *backupCompressor) written(n int) {
+	maxSize := int64(b.rotation.MaxSize) * megabyte
+	if maxSize <= 0 {
+		maxSize = defaultMaxSize * megabyte
+	}
+
+	if b.size+int64(n) >= maxSize {
+		b.size = int64(n)
+		b.notify()
+		return
+	}
+	b.size += int64(n)
+}
+
+// notify wakes the compressor without blocking.
+func (b *backupCompressor) notify() {
+	select {
+	case b.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run compresses the backups whenever woken until closed.
+func (b *backupCompressor) run() {
+	defer b.stopped.Done()
+
+	for {
+		select {
+		case <-b.wake:
+			// Errors can't be reported from here, the files are retried on the next rotation
+			_ = b.compressBackups()
+		case <-b.done:
+			return
+		}
+	}
+}
+
+// close stops the compressor, waiting for the compression in progress to complete.
+func (b *backupCompressor) close() {
+	b.once.Do(func() {
+		close(b.done)
+	})
+	b.stopped.Wait()
+}
+
+// compressBackups compresses the uncompressed backups and removes the compressed ones exceeding the retention.
+func (b *backupCompressor) compressBackups() error {
+	dir := filepath.Dir(b.filename)
+	ext := filepath.Ext(b.filename)
+	prefix := strings.TrimSuffix(filepath.Base(b.filename), ext) + "-"
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	type backup struct {
+		name string
+		time time.Time
+	}
+	var compressed []backup
+	var errs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		switch {
+		case strings.HasSuffix(name, ext+b.ext):
+			if t, err := time.Parse(backupTimeFormat, name[len(prefix):len(name)-len(ext+b.ext)]); err == nil {
+				compressed = append(compressed, backup{name, t})
+			}
+		case strings.HasSuffix(name, ext):
+			t, err := time.Parse(backupTimeFormat, name[len(prefix):len(name)-len(ext)])
+			if err != nil {
+				continue
+			}
+			if err := b.compressFile(filepath.Join(dir, name)); err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			compressed = append(compressed, backup{name + b.ext, t})
+		}
+	}
+
+	// Newest first so that the backups beyond MaxBackups are the oldest
+	sort.Slice(compressed, func(i, j int) bool {
+		return compressed[i].time.After(compressed[j].time)
+	})
+	cutoff := time.Now().Add(-time.Duration(b.rotation.MaxAge) * 24 * time.Hour)
+	for i, c := range compressed {
+		tooMany := b.rotation.MaxBackups > 0 && i >= b.rotation.MaxBackups
+		tooOld := b.rotation.MaxAge > 0 && c.time.Before(cutoff)
+		if tooMany || tooOld {
+			if err := os.Remove(filepath.Join(dir, c.name)); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// compressFile compresses path to path with the compressor's extension and removes path. The compressed file is
+// written to a temporary file in the same directory and renamed once complete, so that it never appears partially
+// written. The temporary file is removed on failure.
+func (b *backupCompressor) compressFile(path string) (err error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return err
+	}
+
+	// The leading dot keeps the temporary file from matching the backups' prefix
+	dst, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+b.ext+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = dst.Close()
+			_ = os.Remove(dst.Name())
+		}
+	}()
+
+	if err = dst.Chmod(info.Mode()); err != nil {
+		return err
+	}
+	w, err := b.newWriter(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(w, src); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+	if err = dst.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(dst.Name(), path+b.ext); err != nil {
+		return err
+	}
+
+	_ = src.Close()
+	return os.Remove(path)
+}
+
+// compressedFile is the rotated file with its backups compressed by a backupCompressor.
+type compressedFile struct {
+	io.Writer
+	compressor *backupCompressor
+}
+
+// Write writes p to the rotated file and wakes the compressor if it's likely rotated.
+func (f *compressedFile) Write(p []byte) (int, error) {
+	n, err := f.Writer.Write(p)
+	f.compressor.written(n)
+
+	return n, err
+}
+
+// SetCompression sets the algorithm compressing rotated files of the default logger. See Logger.SetCompression.
+func SetCompression(algo Compression) error {
+	return std.SetCompression(algo)
+}
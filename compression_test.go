@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// gzipCompressor is a compressor available regardless of build tags.
+var gzipCompressor = compressor{
+	ext: ".gzt",
+	newWriter: func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	},
+}
+
+func TestSetCompression(t *testing.T) {
+
+	l := New(WithConsole(false))
+	require.NoError(t, l.SetCompression(CompressionGzip))
+	require.Error(t, l.SetCompression(Compression(42)))
+
+	if _, ok := compressors[CompressionZstd]; !ok {
+		require.EqualError(t, l.SetCompression(CompressionZstd),
+			"compression zstd is not available, zstd requires building with the zstd tag")
+	}
+}
+
+func TestCompressBackups(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_compress_*")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	filename := filepath.Join(dir, "app.log")
+	now := time.Now().UTC()
+	backup := func(age time.Duration) string {
+		return "app-" + now.Add(-age).Format(backupTimeFormat) + ".log"
+	}
+	files := []string{"app.log", "other.log", "app-notatime.log", backup(time.Hour), backup(2 * time.Hour),
+		backup(3 * time.Hour), backup(10 * 24 * time.Hour)}
+	for _, name := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+
+	b := &backupCompressor{
+		compressor: gzipCompressor,
+		filename:   filename,
+		rotation:   RotationConfig{MaxBackups: 2, MaxAge: 7},
+	}
+	require.NoError(t, b.compressBackups())
+
+	// The active and unrelated files are left alone, the two newest backups are kept compressed
+	var names []string
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	expected := []string{"app.log", "other.log", "app-notatime.log", backup(time.Hour) + ".gzt", backup(2*time.Hour) + ".gzt"}
+	sort.Strings(expected)
+	require.Equal(t, expected, names)
+
+	f, err := os.Open(filepath.Join(dir, backup(time.Hour)+".gzt"))
+	require.NoError(t, err)
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, backup(time.Hour), string(content))
+}
+
+func TestCompressedRotation(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_compress_*")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	compressors[Compression(42)] = gzipCompressor
+	defer delete(compressors, Compression(42))
+
+	filename := filepath.Join(dir, "app.log")
+	l := New(WithConsole(false), WithFilePath(filename), WithRotationConfig(RotationConfig{MaxSize: 1, Compress: true}))
+	require.NoError(t, l.SetCompression(Compression(42)))
+	defer l.Close()
+
+	message := strings.Repeat("x", 64*1024)
+	for i := 0; i < 20; i++ {
+		l.Infof("%s", message)
+	}
+
+	require.Eventually(t, func() bool {
+		matches, _ := filepath.Glob(filepath.Join(dir, "app-*.log.gzt"))
+		plain, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+		return len(matches) == 1 && len(plain) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The active file stays uncompressed
+	content, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "INFO ")
+}
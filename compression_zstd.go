@@ -0,0 +1,19 @@
+//go:build zstd
+// +build zstd
+
+package logger
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func init() {
+	compressors[CompressionZstd] = compressor{
+		ext: ".zst",
+		newWriter: func(w io.Writer) (io.WriteCloser, error) {
+			return zstd.NewWriter(w)
+		},
+	}
+}
@@ -0,0 +1,58 @@
+//go:build zstd
+// +build zstd
+
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestZstdRotation(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_zstd_*")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	filename := filepath.Join(dir, "app.log")
+	l := New(WithConsole(false), WithFilePath(filename), WithRotationConfig(RotationConfig{MaxSize: 1, Compress: true}))
+	require.NoError(t, l.SetCompression(CompressionZstd))
+	defer l.Close()
+
+	message := strings.Repeat("x", 64*1024)
+	for i := 0; i < 20; i++ {
+		l.Infof("%s", message)
+	}
+
+	var matches []string
+	require.Eventually(t, func() bool {
+		matches, _ = filepath.Glob(filepath.Join(dir, "app-*.log.zst"))
+		return len(matches) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Close waits for the compression in progress, which leaves neither the backup nor a temporary file behind
+	require.NoError(t, l.Close())
+	leftovers, err := filepath.Glob(filepath.Join(dir, "*app-*.log*"))
+	require.NoError(t, err)
+	require.Equal(t, matches, leftovers)
+
+	f, err := os.Open(matches[0])
+	require.NoError(t, err)
+	defer f.Close()
+	r, err := zstd.NewReader(f)
+	require.NoError(t, err)
+	defer r.Close()
+	content, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(content), "INFO "))
+	require.Contains(t, string(content), message)
+}
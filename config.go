@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Config is a snapshot of the settings of a Logger, e.g. to verify that the environment and programmatic configuration
+// took effect. Changing it doesn't affect the logger.
+type Config struct {
+	// FilePath is the path of the log file, written unless FileLogging is false or an output is set by SetOutput.
+	FilePath    string
+	FileLogging bool
+	// Console reports whether the lines are written to the console too, e.g. set by LOG_TO_CONSOLE.
+	Console bool
+	// CustomOutput reports whether the lines are written to an output set by SetOutput instead of the log file.
+	CustomOutput bool
+
+	Level  logrus.Level
+	Format Format
+	Prefix string
+	// Version is the version rendered in the lines, the application version unless set by SetVersion.
+	Version string
+
+	TimeFormat      string
+	TimePrecision   TimePrecision
+	ReportTimestamp bool
+	ReportCaller    bool
+	CallerFormat    CallerFormat
+	CallerStyle     CallerStyle
+	// FunctionNameStyle is how the function field names the caller's function.
+	FunctionNameStyle FunctionNameStyle
+
+	Rotation    RotationConfig
+	RotateDaily bool
+	Compression Compression
+	// MaintenanceInterval is the interval of the maintenance of the rotated files, zero if it's disabled.
+	MaintenanceInterval time.Duration
+
+	// AsyncBufferSize is the number of lines buffered by asynchronous writing, zero if it's disabled.
+	AsyncBufferSize int
+}
+
+// Config returns a snapshot of the current settings.
+func (l *Logger) Config() Config {
+	cfg := Config{
+		Level:             l.log.GetLevel(),
+		ReportCaller:      atomic.LoadInt32(&l.noCaller) == 0,
+		FunctionNameStyle: FunctionNameStyle(atomic.LoadInt32(&l.functionStyle)),
+	}
+
+	l.mu.Lock()
+	cfg.FilePath = l.filePath
+	cfg.FileLogging = !l.noFile
+	cfg.Console = l.console
+	cfg.CustomOutput = l.output != nil
+	cfg.Rotation = l.rotation
+	cfg.RotateDaily = l.daily
+	cfg.Compression = l.compression
+	cfg.MaintenanceInterval = l.maintenanceInterval
+	cfg.AsyncBufferSize = l.asyncConfig.bufferSize
+	l.mu.Unlock()
+
+	f := l.formatter
+	f.mu.RLock()
+	cfg.Format = f.format
+	cfg.Prefix = f.prefix
+	cfg.Version = f.appVersion()
+	cfg.TimeFormat = f.timeFormat
+	cfg.TimePrecision = f.precision
+	cfg.ReportTimestamp = !f.noTimestamp
+	cfg.CallerFormat = f.caller
+	cfg.CallerStyle = f.style
+	f.mu.RUnlock()
+
+	return cfg
+}
+
+// GetConfig returns a snapshot of the current settings of the default logger, named apart from the Config type. See
+// Logger.Config.
+func GetConfig() Config {
+	return std.Config()
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+
+	path := filepath.Join(os.TempDir(), "_logger_config.log")
+	l := New(WithFilePath(path), WithConsole(false), WithFileLogging(false), WithLevel(logrus.DebugLevel),
+		WithFormat(FormatJSON), WithRotationConfig(RotationConfig{MaxSize: 5, MaxAge: 7}))
+
+	cfg := l.Config()
+	require.Equal(t, path, cfg.FilePath)
+	require.False(t, cfg.FileLogging)
+	require.False(t, cfg.Console)
+	require.False(t, cfg.CustomOutput)
+	require.Equal(t, logrus.DebugLevel, cfg.Level)
+	require.Equal(t, FormatJSON, cfg.Format)
+	require.Equal(t, RotationConfig{MaxSize: 5, MaxAge: 7}, cfg.Rotation)
+	require.False(t, cfg.RotateDaily)
+	require.True(t, cfg.ReportCaller)
+	require.True(t, cfg.ReportTimestamp)
+
+	l.SetPrefix("[api] ")
+	l.SetReportCaller(false)
+	l.SetReportTimestamp(false)
+	cfg = l.Config()
+	require.Equal(t, "[api] ", cfg.Prefix)
+	require.False(t, cfg.ReportCaller)
+	require.False(t, cfg.ReportTimestamp)
+
+	// The snapshot is a copy
+	cfg.Rotation.MaxSize = 100
+	require.Equal(t, 5, l.Config().Rotation.MaxSize)
+}
+
+func TestGetConfig(t *testing.T) {
+
+	defer ResetForTest()
+
+	SetLevelL(logrus.WarnLevel)
+	cfg := GetConfig()
+	require.Equal(t, logrus.WarnLevel, cfg.Level)
+	require.Equal(t, defaultRotationConfig(), cfg.Rotation)
+}
@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ANSI escape codes used to colorize the level of console output
+const (
+	colorRed    = "\x1b[31m"
+	colorYellow = "\x1b[33m"
+	colorBlue   = "\x1b[36m"
+	colorGray   = "\x1b[37m"
+	colorReset  = "\x1b[0m"
+)
+
+// consoleHook writes entries to the console. It's separate from the logger's output so that console lines can be
+// formatted differently than the file, e.g. colorized.
+type consoleHook struct {
+	logger *Logger
+
+	mu          sync.RWMutex
+	out         io.Writer
+	errOut      io.Writer
+	errLevel    logrus.Level
+	colorized   bool
+	forceColors bool
+
+	// writeMu serializes the writes to the console so that concurrent lines never interleave, even on writers which
+	// split a write, and lines on stdout and stderr keep their order
+	writeMu sync.Mutex
+}
+
+// newConsoleHook returns a consoleHook of l writing Warn level and above to the error writer once it's set.
+func newConsoleHook(l *Logger) *consoleHook {
+	return &consoleHook{logger: l, errLevel: logrus.WarnLevel}
+}
+
+// setOutput sets the console writer, nil disables console output.
+func (h *consoleHook) setOutput(out io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.out = out
+}
+
+// setErrOutput sets the writer of entries at the error level and above, nil writes them to the console writer too.
+func (h *consoleHook) setErrOutput(errOut io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.errOut = errOut
+}
+
+// setErrLevel sets the level at and above which entries are written to the error writer.
+func (h *consoleHook) setErrLevel(level logrus.Level) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.errLevel = level
+}
+
+// output returns the console writer, nil if console output is disabled.
+func (h *consoleHook) output() io.Writer {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.out
+}
+
+// setColorized enables or disables colorized output.
+func (h *consoleHook) setColorized(enabled bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.colorized = enabled
+}
+
+// setForceColors makes colorized output be written even if the console is not a terminal.
+func (h *consoleHook) setForceColors(force bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.forceColors = force
+}
+
+// Levels returns the levels the hook fires for.
+func (h *consoleHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire writes entry to the console if console output is enabled, reporting the errors to the internal error handler.
+func (h *consoleHook) Fire(entry *logrus.Entry) error {
+	h.mu.RLock()
+	out := h.out
+	if h.errOut != nil && entry.Level <= h.errLevel {
+		out = h.errOut
+	}
+	colorized := h.colorized && (h.forceColors || isTerminal(out))
+	h.mu.RUnlock()
+
+	if out == nil {
+		return nil
+	}
+
+	line, err := h.logger.formatter.formatConsole(entry, colorized)
+	if err != nil {
+		h.logger.internalError(fmt.Errorf("failed to format console line: %w", err))
+		return nil
+	}
+
+	if _, err := h.write(out, line); err != nil {
+		h.logger.internalError(fmt.Errorf("failed to write to console: %w", err))
+	}
+
+	return nil
+}
+
+// write writes the whole of p to out in a single call, serialized with the other console writes.
+func (h *consoleHook) write(out io.Writer, p []byte) (int, error) {
+	h.writeMu.Lock()
+	defer h.writeMu.Unlock()
+
+	return out.Write(p)
+}
+
+// consoleWriter writes to the console writer of hook as the log lines do.
+type consoleWriter struct {
+	hook *consoleHook
+	out  io.Writer
+}
+
+// Write writes p to the console, serialized with the log lines.
+func (w consoleWriter) Write(p []byte) (int, error) {
+	return w.hook.write(w.out, p)
+}
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// levelColor returns the escape code colorizing level.
+func levelColor(level logrus.Level) string {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		return colorRed
+	case logrus.WarnLevel:
+		return colorYellow
+	case logrus.InfoLevel:
+		return colorBlue
+	default:
+		return colorGray
+	}
+}
@@ -0,0 +1,262 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetColorized(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_console_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+
+	path := filepath.Join(dir, "app.log")
+	l := New(WithFilePath(path), WithConsole(true))
+
+	var console bytes.Buffer
+	l.consoleHook.setOutput(&console)
+	l.consoleHook.setErrOutput(&console)
+	l.SetColorized(true)
+
+	// Console is not a terminal
+	message := randStringBytes(30)
+	l.Errorf("%s", message)
+	require.Contains(t, console.String(), "ERROR ")
+	require.NotContains(t, console.String(), "\x1b[")
+
+	console.Reset()
+	l.SetForceColors(true)
+	l.Errorf("%s", message)
+	l.Warnf("%s", message)
+	require.Contains(t, console.String(), colorRed+"ERROR"+colorReset+" ")
+	require.Contains(t, console.String(), colorYellow+"WARNING"+colorReset+" ")
+
+	// Colors never leak into the file
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), message)
+	require.NotContains(t, string(content), "\x1b[")
+}
+
+func TestSetConsoleAndFileFormat(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_console_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+
+	path := filepath.Join(dir, "app.log")
+	l := New(WithFilePath(path), WithConsole(true))
+
+	// Redirect stdout to pipe
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	l.consoleHook.setOutput(os.Stdout)
+
+	l.SetConsoleFormat(FormatText)
+	l.SetFileFormat(FormatJSON)
+
+	message := randStringBytes(30)
+	l.Infof("%s", message)
+
+	// Catch stdout content from pipe
+	outC := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		outC <- buf.String()
+	}()
+	_ = w.Close()
+	console := <-outC
+	os.Stdout = old
+
+	split := strings.Split(console, " ")
+	require.Equal(t, "INFO", split[0])
+	require.Equal(t, message, split[3])
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	err = json.Unmarshal(content, &decoded)
+	require.NoError(t, err)
+	require.Equal(t, message, decoded["message"])
+
+	// SetFormat doesn't affect overridden destinations
+	l.SetFormat(FormatJSON)
+	require.Equal(t, FormatText, l.formatter.consoleFormat.or(l.formatter.format))
+}
+
+func TestSetFileLogging(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_console_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+
+	// Redirect stdout and stderr to files
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	require.NoError(t, err)
+	defer stdout.Close()
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	require.NoError(t, err)
+	defer stderr.Close()
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	defer func() {
+		os.Stdout, os.Stderr = oldStdout, oldStderr
+	}()
+
+	path := filepath.Join(dir, "app.log")
+	l := New(WithFilePath(path), WithConsole(false), WithFileLogging(false))
+	l.Infof("%s", "info")
+	l.Warnf("%s", "warning")
+	l.Errorf("%s", "error")
+	require.NoError(t, l.Close())
+
+	// No file is created
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+
+	out, err := ioutil.ReadFile(stdout.Name())
+	require.NoError(t, err)
+	require.Contains(t, string(out), "INFO ")
+	require.NotContains(t, string(out), "WARNING ")
+	require.NotContains(t, string(out), "ERROR ")
+
+	errOut, err := ioutil.ReadFile(stderr.Name())
+	require.NoError(t, err)
+	require.Contains(t, string(errOut), "WARNING ")
+	require.Contains(t, string(errOut), "ERROR ")
+	require.NotContains(t, string(errOut), "INFO ")
+
+	// Enabling the file logging writes to the file again
+	l.SetFileLogging(true)
+	l.Infof("%s", "file")
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), " file file:")
+	require.NoError(t, l.Close())
+}
+
+func TestSetStdErrThreshold(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_console_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+
+	// Redirect stdout and stderr to pipes
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	outR, outW, _ := os.Pipe()
+	errR, errW, _ := os.Pipe()
+	os.Stdout, os.Stderr = outW, errW
+
+	l := New(WithFilePath(filepath.Join(dir, "app.log")), WithConsole(true))
+	l.SetStdErrThreshold(logrus.ErrorLevel)
+	l.Infof("%s", "info")
+	l.Warnf("%s", "warning")
+	l.Errorf("%s", "error")
+	require.NoError(t, l.Close())
+
+	read := func(r io.Reader) <-chan string {
+		c := make(chan string)
+		go func() {
+			var buf bytes.Buffer
+			_, _ = io.Copy(&buf, r)
+			c <- buf.String()
+		}()
+		return c
+	}
+	outC, errC := read(outR), read(errR)
+	_ = outW.Close()
+	_ = errW.Close()
+	stdout, stderr := <-outC, <-errC
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+
+	require.Contains(t, stdout, "INFO ")
+	require.Contains(t, stdout, "WARNING ")
+	require.NotContains(t, stdout, "ERROR ")
+	require.Contains(t, stderr, "ERROR ")
+	require.NotContains(t, stderr, "INFO ")
+	require.NotContains(t, stderr, "WARNING ")
+}
+
+// splittingWriter writes byte by byte, yielding in between, like a writer which doesn't write atomically.
+type splittingWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *splittingWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		w.mu.Lock()
+		w.buf.WriteByte(b)
+		w.mu.Unlock()
+		runtime.Gosched()
+	}
+
+	return len(p), nil
+}
+
+func TestConsoleConcurrentLines(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_console_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+
+	l := New(WithFilePath(filepath.Join(dir, "app.log")), WithConsole(true))
+	defer l.Close()
+
+	var console splittingWriter
+	l.consoleHook.setOutput(&console)
+	l.consoleHook.setErrOutput(&console)
+
+	const goroutines, lines = 8, 20
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(message string) {
+			defer wg.Done()
+			for j := 0; j < lines; j++ {
+				if j%2 == 0 {
+					l.Warnf("%s", message)
+				} else {
+					l.Infof("%s", message)
+				}
+			}
+		}(strings.Repeat(string(rune('a'+i)), 200))
+	}
+	wg.Wait()
+
+	output := strings.Split(strings.TrimSuffix(console.buf.String(), "\n"), "\n")
+	require.Len(t, output, goroutines*lines)
+	for _, line := range output {
+		var intact bool
+		for i := 0; i < goroutines; i++ {
+			intact = intact || strings.Contains(line, strings.Repeat(string(rune('a'+i)), 200))
+		}
+		require.True(t, intact, "garbled line %q", line)
+		require.Equal(t, 1, strings.Count(line, " 1.0.0 "), "garbled line %q", line)
+	}
+}
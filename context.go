@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ContextExtractor returns the fields to attach to a log line from ctx, e.g. trace_id and span_id of a tracing span.
+type ContextExtractor func(ctx context.Context) map[string]interface{}
+
+// RegisterContextExtractor registers extractor whose fields are attached to messages logged by the ...Ctx helpers.
+// Extractors run in registration order, fields of later extractors override earlier ones on key collision, and the
+// fields stored by ContextWithFields override them all.
+func (l *Logger) RegisterContextExtractor(extractor ContextExtractor) {
+	l.ctxMu.Lock()
+	defer l.ctxMu.Unlock()
+
+	l.extractors = append(l.extractors, extractor)
+}
+
+// fieldsKey is the context key of the fields stored by ContextWithFields.
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying fields, which are attached to the messages logged with it by the
+// ...Ctx helpers of any logger, e.g. to stash the fields of a request once in a middleware. Fields already carried by
+// ctx are kept, fields overrides them on key collision. The fields are copied, modifying them afterwards doesn't affect
+// ctx.
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, copyFields(FieldsFromContext(ctx), fields))
+}
+
+// FieldsFromContext returns a copy of the fields carried by ctx, nil if there are none.
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	if ctx == nil {
+		return nil
+	}
+
+	fields, _ := ctx.Value(fieldsKey{}).(logrus.Fields)
+	if fields == nil {
+		return nil
+	}
+
+	return copyFields(fields, nil)
+}
+
+// TracefCtx logs a message at level Trace with the fields extracted from ctx.
+func (l *Logger) TracefCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logfCtx(ctx, logrus.TraceLevel, format, args...)
+}
+
+// DebugfCtx logs a message at level Debug with the fields extracted from ctx.
+func (l *Logger) DebugfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logfCtx(ctx, logrus.DebugLevel, format, args...)
+}
+
+// InfofCtx logs a message at level Info with the fields extracted from ctx.
+func (l *Logger) InfofCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logfCtx(ctx, logrus.InfoLevel, format, args...)
+}
+
+// WarnfCtx logs a message at level Warn with the fields extracted from ctx.
+func (l *Logger) WarnfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logfCtx(ctx, logrus.WarnLevel, format, args...)
+}
+
+// ErrorfCtx logs a message at level Error with the fields extracted from ctx.
+func (l *Logger) ErrorfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logfCtx(ctx, logrus.ErrorLevel, format, args...)
+}
+
+// FatalfCtx logs a message at level Fatal with the fields extracted from ctx and exits.
+func (l *Logger) FatalfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logfCtx(ctx, logrus.FatalLevel, format, args...)
+	l.exit()
+}
+
+// PanicfCtx logs a message at level Panic with the fields extracted from ctx and panics.
+func (l *Logger) PanicfCtx(ctx context.Context, format string, args ...interface{}) {
+	l.logfCtx(ctx, logrus.PanicLevel, format, args...)
+}
+
+// logfCtx logs a formatted message with the fields extracted from ctx if level is enabled. Exported helpers must call
+// it directly so that the caller frame is always skipFrameCount frames away.
+func (l *Logger) logfCtx(ctx context.Context, level logrus.Level, format string, args ...interface{}) {
+	if !l.isLevelEnabled(level) {
+		return
+	}
+
+	if entry := l.newEntry(0, level, l.contextFields(ctx), true); entry != nil {
+		l.write(entry, level, fmt.Sprintf(format, args...))
+	}
+}
+
+// contextFields runs the registered extractors on ctx and adds the fields stored by ContextWithFields, which override
+// the extracted ones. It returns nil for a nil ctx or if there are neither extractors nor stored fields.
+func (l *Logger) contextFields(ctx context.Context) logrus.Fields {
+	if ctx == nil {
+		return nil
+	}
+
+	l.ctxMu.RLock()
+	defer l.ctxMu.RUnlock()
+
+	var fields logrus.Fields
+	for _, extractor := range l.extractors {
+		fields = copyFields(fields, extractor(ctx))
+	}
+	if stored, _ := ctx.Value(fieldsKey{}).(logrus.Fields); stored != nil {
+		fields = copyFields(fields, stored)
+	}
+
+	return fields
+}
+
+// RegisterContextExtractor registers extractor on the default logger. See Logger.RegisterContextExtractor.
+func RegisterContextExtractor(extractor ContextExtractor) {
+	std.RegisterContextExtractor(extractor)
+}
+
+// TracefCtx logs a message at level Trace on the standard logger with the fields extracted from ctx.
+func TracefCtx(ctx context.Context, format string, args ...interface{}) {
+	std.logfCtx(ctx, logrus.TraceLevel, format, args...)
+}
+
+// DebugfCtx logs a message at level Debug on the standard logger with the fields extracted from ctx.
+func DebugfCtx(ctx context.Context, format string, args ...interface{}) {
+	std.logfCtx(ctx, logrus.DebugLevel, format, args...)
+}
+
+// InfofCtx logs a message at level Info on the standard logger with the fields extracted from ctx.
+func InfofCtx(ctx context.Context, format string, args ...interface{}) {
+	std.logfCtx(ctx, logrus.InfoLevel, format, args...)
+}
+
+// WarnfCtx logs a message at level Warn on the standard logger with the fields extracted from ctx.
+func WarnfCtx(ctx context.Context, format string, args ...interface{}) {
+	std.logfCtx(ctx, logrus.WarnLevel, format, args...)
+}
+
+// ErrorfCtx logs a message at level Error on the standard logger with the fields extracted from ctx.
+func ErrorfCtx(ctx context.Context, format string, args ...interface{}) {
+	std.logfCtx(ctx, logrus.ErrorLevel, format, args...)
+}
+
+// FatalfCtx logs a message at level Fatal on the standard logger with the fields extracted from ctx and exits.
+func FatalfCtx(ctx context.Context, format string, args ...interface{}) {
+	std.logfCtx(ctx, logrus.FatalLevel, format, args...)
+	std.exit()
+}
+
+// PanicfCtx logs a message at level Panic on the standard logger with the fields extracted from ctx and panics.
+func PanicfCtx(ctx context.Context, format string, args ...interface{}) {
+	std.logfCtx(ctx, logrus.PanicLevel, format, args...)
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type traceKey struct{}
+
+func TestInfofCtx(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	ctx := context.WithValue(context.Background(), traceKey{}, "4bf92f3577b34da6")
+	message := randStringBytes(30)
+
+	// Without extractors the output is identical to Infof
+	l.InfofCtx(ctx, "%s", message)
+	require.True(t, strings.HasSuffix(strings.TrimSpace(buf.String()), "func:.TestInfofCtx"))
+
+	l.RegisterContextExtractor(func(ctx context.Context) map[string]interface{} {
+		traceID, ok := ctx.Value(traceKey{}).(string)
+		if !ok {
+			return nil
+		}
+		return map[string]interface{}{"trace_id": traceID}
+	})
+
+	buf.Reset()
+	l.InfofCtx(ctx, "%s", message)
+	require.True(t, strings.HasSuffix(strings.TrimSpace(buf.String()), " trace_id=4bf92f3577b34da6"))
+	require.Contains(t, buf.String(), "context_test.go:")
+
+	// Nil context behaves like Infof
+	var nilCtx context.Context
+	buf.Reset()
+	l.InfofCtx(nilCtx, "%s", message)
+	require.NotContains(t, buf.String(), "trace_id")
+
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.ErrorfCtx(ctx, "%s", message)
+
+	var decoded map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &decoded)
+	require.NoError(t, err)
+	require.Equal(t, "4bf92f3577b34da6", decoded["trace_id"])
+	require.Equal(t, message, decoded["message"])
+}
+
+func TestContextWithFields(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.RegisterContextExtractor(func(ctx context.Context) map[string]interface{} {
+		return map[string]interface{}{"user": "extracted", "trace_id": "4bf92f3577b34da6"}
+	})
+
+	// A middleware stores the request fields, a handler down the chain adds its own
+	fields := map[string]interface{}{"request_id": "abc-123", "user": "alice"}
+	ctx := ContextWithFields(context.Background(), fields)
+	fields["request_id"] = "changed"
+	handlerCtx := ContextWithFields(ctx, map[string]interface{}{"user": "bob", "step": "save"})
+
+	l.InfofCtx(handlerCtx, "saved")
+	line := strings.TrimSpace(buf.String())
+	require.True(t, strings.HasSuffix(line, " request_id=abc-123 step=save trace_id=4bf92f3577b34da6 user=bob"), line)
+
+	// The outer context is unchanged
+	require.Equal(t, map[string]interface{}{"request_id": "abc-123", "user": "alice"}, FieldsFromContext(ctx))
+	require.Nil(t, FieldsFromContext(context.Background()))
+
+	// The returned fields are a copy
+	FieldsFromContext(ctx)["user"] = "mallory"
+	require.Equal(t, "alice", FieldsFromContext(ctx)["user"])
+}
@@ -0,0 +1,178 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dailyDateFormat is the layout of the date in the names of the files rotated daily
+const dailyDateFormat = "2006-01-02"
+
+// RotateDaily enables or disables daily rotation, disabled by default. When enabled the lines of each local calendar
+// day are written to their own file named after the log file with the date, e.g. app-2024-01-23.log for app.log, and
+// a new file is started at local midnight. Files older than RotationConfig.MaxAge days are removed, MaxBackups and
+// Compress don't apply. Daily and size-based rotation are mutually exclusive: it returns an error if MaxSize has been
+// set by SetRotationConfig, WithRotationConfig or WithMaxSizeMB.
+func (l *Logger) RotateDaily(enabled bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if enabled == l.daily {
+		return nil
+	}
+	if enabled && l.sizeRotation {
+		return fmt.Errorf("daily rotation can't be combined with size-based rotation of %d MB, set MaxSize to zero",
+			l.rotation.MaxSize)
+	}
+
+	l.daily = enabled
+	if enabled {
+		l.rotation.MaxSize = 0
+	} else if l.rotation.MaxSize == 0 {
+		l.rotation.MaxSize = maxSizeInMBs
+	}
+	l.log.SetOutput(l.getWriter())
+
+	return nil
+}
+
+// dailyFile writes to a file per local calendar day, switching files on the first write after midnight.
+type dailyFile struct {
+	filename string
+	maxAge   int
+	// now returns the current time, replaced by tests to cross midnight
+	now func() time.Time
+
+	mu   sync.Mutex
+	file *os.File
+	day  string
+}
+
+// newDailyFile returns a dailyFile of the files named after filename, retaining maxAge days. The file is opened on the
+// first write.
+func newDailyFile(filename string, maxAge int, now func() time.Time) *dailyFile {
+	return &dailyFile{filename: filename, maxAge: maxAge, now: now}
+}
+
+// dailyFileName returns the name of the file of day for filename, e.g. app-2024-01-23.log for app.log.
+func dailyFileName(filename string, day time.Time) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "-" + day.Format(dailyDateFormat) + ext
+}
+
+// Write writes p to the file of the current day, opening it if the day changed.
+func (d *dailyFile) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	if day := now.Format(dailyDateFormat); d.file == nil || day != d.day {
+		if err := d.open(now); err != nil {
+			return 0, err
+		}
+	}
+
+	return d.file.Write(p)
+}
+
+// open closes the current file, opens the file of now's day and removes the expired files.
+func (d *dailyFile) open(now time.Time) error {
+	if d.file != nil {
+		_ = d.file.Close()
+		d.file = nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(d.filename), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(dailyFileName(d.filename, now), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	d.file = f
+	d.day = now.Format(dailyDateFormat)
+
+	// Errors can't be reported from here, the files are retried the next day
+	_ = d.removeExpired(now)
+
+	return nil
+}
+
+// removeExpired removes the files of the days before the last maxAge days, keeping all of them if maxAge is zero.
+func (d *dailyFile) removeExpired(now time.Time) error {
+	if d.maxAge <= 0 {
+		return nil
+	}
+
+	dir := filepath.Dir(d.filename)
+	ext := filepath.Ext(d.filename)
+	prefix := strings.TrimSuffix(filepath.Base(d.filename), ext) + "-"
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	year, month, day := now.Date()
+	cutoff := time.Date(year, month, day, 0, 0, 0, 0, now.Location()).AddDate(0, 0, -d.maxAge)
+
+	var errs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+
+		date, err := time.ParseInLocation(dailyDateFormat, name[len(prefix):len(name)-len(ext)], now.Location())
+		if err != nil || !date.Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// Sync commits the current file to disk.
+func (d *dailyFile) Sync() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.file == nil {
+		return nil
+	}
+
+	return d.file.Sync()
+}
+
+// Close closes the current file, the next write opens it again.
+func (d *dailyFile) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.file == nil {
+		return nil
+	}
+
+	err := d.file.Close()
+	d.file = nil
+
+	return err
+}
+
+// RotateDaily enables or disables daily rotation of the default logger's log file. See Logger.RotateDaily.
+func RotateDaily(enabled bool) error {
+	return std.RotateDaily(enabled)
+}
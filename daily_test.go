@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailyFile(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_daily_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "app.log")
+	now := time.Date(2024, 1, 23, 23, 59, 59, 0, time.Local)
+	d := newDailyFile(filename, 0, func() time.Time { return now })
+	defer d.Close()
+
+	_, err = d.Write([]byte("before midnight\n"))
+	require.NoError(t, err)
+
+	now = now.Add(2 * time.Second)
+	_, err = d.Write([]byte("after midnight\n"))
+	require.NoError(t, err)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "app-2024-01-23.log"))
+	require.NoError(t, err)
+	require.Equal(t, "before midnight\n", string(content))
+
+	content, err = ioutil.ReadFile(filepath.Join(dir, "app-2024-01-24.log"))
+	require.NoError(t, err)
+	require.Equal(t, "after midnight\n", string(content))
+
+	_, err = os.Stat(filename)
+	require.True(t, os.IsNotExist(err))
+}
+
+func TestDailyFileMaxAge(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_daily_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"app-2024-01-20.log", "app-2024-01-21.log", "app-2024-01-22.log", "other-2024-01-01.log"} {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+
+	now := time.Date(2024, 1, 23, 12, 0, 0, 0, time.Local)
+	d := newDailyFile(filepath.Join(dir, "app.log"), 2, func() time.Time { return now })
+	defer d.Close()
+
+	_, err = d.Write([]byte("line\n"))
+	require.NoError(t, err)
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	require.Equal(t, []string{"app-2024-01-21.log", "app-2024-01-22.log", "app-2024-01-23.log", "other-2024-01-01.log"}, names)
+}
+
+func TestRotateDaily(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_daily_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l := New(WithConsole(false), WithFilePath(filepath.Join(dir, "app.log")))
+	defer l.Close()
+
+	require.NoError(t, l.RotateDaily(true))
+	l.Infof("daily")
+
+	content, err := ioutil.ReadFile(dailyFileName(filepath.Join(dir, "app.log"), time.Now()))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "daily")
+
+	// Size-based rotation can't be enabled at the same time
+	require.Error(t, l.SetRotationConfig(RotationConfig{MaxSize: 1}))
+	require.NoError(t, l.SetRotationConfig(RotationConfig{MaxAge: 7}))
+
+	require.NoError(t, l.RotateDaily(false))
+	require.NoError(t, l.SetRotationConfig(RotationConfig{MaxSize: 1}))
+	require.Error(t, l.RotateDaily(true))
+}
+
+func TestRotateDailyWithMaxSize(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_daily_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l := New(WithConsole(false), WithFilePath(filepath.Join(dir, "app.log")), WithMaxSizeMB(5))
+	defer l.Close()
+
+	err = l.RotateDaily(true)
+	require.EqualError(t, err, "daily rotation can't be combined with size-based rotation of 5 MB, set MaxSize to zero")
+}
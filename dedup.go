@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// deduper suppresses consecutive identical messages and logs a summary of their count instead.
+type deduper struct {
+	window time.Duration
+	log    func(entry *logrus.Entry, level logrus.Level, msg string)
+
+	mu       sync.Mutex
+	last     *logrus.Entry
+	lastKey  string
+	level    logrus.Level
+	repeated int
+	timer    *time.Timer
+}
+
+// newDeduper creates a deduper counting repetitions for at most window and writing the summaries with log.
+func newDeduper(window time.Duration, log func(entry *logrus.Entry, level logrus.Level, msg string)) *deduper {
+	return &deduper{window: window, log: log}
+}
+
+// add reports whether msg should be logged. Repetitions of the last message are counted instead, a different message
+// writes out the summary of the last one first. Fatal and panic messages are always logged.
+func (d *deduper) add(entry *logrus.Entry, level logrus.Level, msg string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if level <= logrus.FatalLevel {
+		d.writeSummary()
+		return true
+	}
+
+	key := dedupKey(entry, level, msg)
+	if d.last != nil && key == d.lastKey {
+		d.repeated++
+		if d.timer == nil {
+			d.timer = time.AfterFunc(d.window, d.flush)
+		}
+		return false
+	}
+
+	d.writeSummary()
+	d.last = entry
+	d.lastKey = key
+	d.level = level
+
+	return true
+}
+
+// flush writes out the summary of the pending repetitions.
+func (d *deduper) flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.writeSummary()
+}
+
+// writeSummary logs the repeat count of the last message with its level and caller and starts over, callers must hold
+// d.mu.
+func (d *deduper) writeSummary() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+
+	if d.last != nil && d.repeated > 0 {
+		d.log(d.last, d.level, fmt.Sprintf("last message repeated %d times", d.repeated))
+	}
+
+	d.last = nil
+	d.lastKey = ""
+	d.repeated = 0
+}
+
+// dedupKey identifies a message by its level, text and caller.
+func dedupKey(entry *logrus.Entry, level logrus.Level, msg string) string {
+	file, _ := entry.Data["file"].(string)
+	line, _ := entry.Data["line"].(int)
+
+	return level.String() + "|" + file + ":" + strconv.Itoa(line) + "|" + msg
+}
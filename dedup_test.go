@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetDedup(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetDedup(true, time.Hour)
+
+	repeat := func() {
+		l.Warnf("%s", "disk almost full")
+	}
+
+	for i := 0; i < 43; i++ {
+		repeat()
+	}
+	l.Infof("%s", "cleanup started")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+	require.Contains(t, lines[0], "disk almost full")
+
+	// Summary keeps the level and caller of the repeated message
+	require.True(t, strings.HasPrefix(lines[1], "WARNING "))
+	require.Contains(t, lines[1], "last message repeated 42 times")
+	require.Equal(t, lines[0][strings.Index(lines[0], " file:"):], lines[1][strings.Index(lines[1], " file:"):])
+	require.Contains(t, lines[2], "cleanup started")
+
+	// Pending summary is written on flush
+	buf.Reset()
+	repeat()
+	repeat()
+	require.NoError(t, l.Flush())
+	require.Contains(t, buf.String(), "last message repeated 1 times")
+}
+
+func TestSetDedupWindow(t *testing.T) {
+
+	var buf syncBuffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetDedup(true, 50*time.Millisecond)
+
+	repeat := func() {
+		l.Warnf("%s", "disk almost full")
+	}
+
+	repeat()
+	repeat()
+	repeat()
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "last message repeated 2 times")
+	}, time.Second, 10*time.Millisecond)
+}
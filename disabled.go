@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetEnabled turns all logging on or off, enabled by default. While disabled the helpers return right after checking
+// this switch, without resolving the caller, formatting the message or firing hooks, which makes logging as cheap as
+// possible in benchmarks and load tests. Fatal and panic messages are still logged since the process ends right after.
+// Unlike a high level it's a single switch, SetLevel keeps the level to restore when logging is enabled again.
+func (l *Logger) SetEnabled(enabled bool) {
+	var disabled int32
+	if !enabled {
+		disabled = 1
+	}
+	atomic.StoreInt32(&l.disabled, disabled)
+}
+
+// Enabled reports whether logging is enabled, see SetEnabled.
+func (l *Logger) Enabled() bool {
+	return atomic.LoadInt32(&l.disabled) == 0
+}
+
+// isLevelEnabled reports whether messages at level are logged, checked by every helper before doing any work.
+func (l *Logger) isLevelEnabled(level logrus.Level) bool {
+	if atomic.LoadInt32(&l.disabled) != 0 && level > logrus.FatalLevel {
+		return false
+	}
+
+	return l.log.IsLevelEnabled(level)
+}
+
+// SetEnabled turns all logging of the default logger on or off. See Logger.SetEnabled.
+func SetEnabled(enabled bool) {
+	std.SetEnabled(enabled)
+}
+
+// Enabled reports whether logging of the default logger is enabled. See Logger.Enabled.
+func Enabled() bool {
+	return std.Enabled()
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSetEnabled(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithConsole(false), WithFileLogging(false), WithLevel(logrus.DebugLevel))
+	l.SetOutput(&buf)
+
+	l.SetEnabled(false)
+	if l.Enabled() || l.IsDebugEnabled() {
+		t.Fatal("logging should be disabled")
+	}
+	l.Errorf("dropped")
+	l.WithFields(map[string]interface{}{"key": "value"}).Infof("dropped")
+	l.StdLogger(logrus.InfoLevel).Printf("dropped")
+	if buf.Len() != 0 {
+		t.Fatalf("disabled logger wrote %q", buf.String())
+	}
+	if l.GetLevel() != logrus.DebugLevel {
+		t.Fatalf("level changed to %v", l.GetLevel())
+	}
+
+	l.SetExitFunc(func(int) {})
+	l.Fatalf("fatal")
+	if !bytes.Contains(buf.Bytes(), []byte("fatal")) {
+		t.Fatalf("fatal message not logged while disabled: %q", buf.String())
+	}
+
+	buf.Reset()
+	l.SetEnabled(true)
+	l.Debugf("logged")
+	if !l.Enabled() || !bytes.Contains(buf.Bytes(), []byte("logged")) {
+		t.Fatalf("enabled logger wrote %q", buf.String())
+	}
+}
+
+func BenchmarkInfofSetEnabledFalse(b *testing.B) {
+	l := New(WithConsole(false), WithFileLogging(false))
+	l.SetOutput(ioutil.Discard)
+	l.SetEnabled(false)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Infof("%s", "disabled")
+	}
+}
@@ -0,0 +1,253 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is a log entry view carrying custom fields which are attached to every message logged through it, and a
+// prefix prepended to its messages.
+type Entry struct {
+	logger   *Logger
+	fields   logrus.Fields
+	prefix   string
+	noCaller bool
+}
+
+// WithFields returns an Entry which attaches fields to its messages. Keys reserved by the logger, like file, line and
+// function, are prefixed with "fields." instead of overriding the logger's own fields.
+func (l *Logger) WithFields(fields map[string]interface{}) *Entry {
+	return &Entry{logger: l, fields: copyFields(nil, fields)}
+}
+
+// WithFields returns a new Entry with fields merged into the entry's fields, fields override existing keys.
+func (e *Entry) WithFields(fields map[string]interface{}) *Entry {
+	return &Entry{logger: e.logger, fields: copyFields(e.fields, fields), prefix: e.prefix, noCaller: e.noCaller}
+}
+
+// With returns an Entry which attaches fields to its messages, to be built once and reused for the lines sharing the
+// fields, e.g. of a request, rather than calling WithFields for every line:
+//
+//	reqLog := l.With(map[string]interface{}{"request_id": id})
+//	reqLog.Infof("started")
+//
+// The fields are copied once, each line only copies them into its own entry and resolves its caller as usual. The
+// Entry is safe for concurrent use.
+func (l *Logger) With(fields map[string]interface{}) *Entry {
+	return l.WithFields(fields)
+}
+
+// With returns a new Entry with fields merged into the entry's fields, to be reused like Logger.With.
+func (e *Entry) With(fields map[string]interface{}) *Entry {
+	return e.WithFields(fields)
+}
+
+// errorCauses are the messages of the errors wrapped by a logged error from the outermost to the root cause. The text
+// layout renders them as cause1=... cause2=... and JSON as an array.
+type errorCauses []string
+
+// WithError returns an Entry which attaches err under the error field. The errors wrapped by err, e.g. with
+// fmt.Errorf("...: %w", err), are attached under the causes field up to the depth set by SetErrorChainDepth. If err
+// exposes a stack trace through a StackTrace method, like errors of github.com/pkg/errors, it's attached under the
+// stack field. A nil err adds no fields.
+func (l *Logger) WithError(err error) *Entry {
+	return &Entry{logger: l, fields: l.errorFields(nil, err)}
+}
+
+// WithError returns a new Entry with err attached to the entry's fields. See Logger.WithError.
+func (e *Entry) WithError(err error) *Entry {
+	return &Entry{logger: e.logger, fields: e.logger.errorFields(e.fields, err), prefix: e.prefix, noCaller: e.noCaller}
+}
+
+// SetErrorChainDepth sets how many errors wrapped by an error attached with WithError are attached as its causes, 10 by
+// default. Zero disables attaching the causes.
+func (l *Logger) SetErrorChainDepth(depth int) {
+	if depth < 0 {
+		depth = 0
+	}
+
+	atomic.StoreInt32(&l.errorChainDepth, int32(depth))
+}
+
+// WithPrefix returns an Entry which prepends p to its messages, e.g. "[db] " to tag a subsystem. The prefix set by
+// SetPrefix comes first, so with SetPrefix("api: ") the messages read "api: [db] message".
+func (l *Logger) WithPrefix(p string) *Entry {
+	return &Entry{logger: l, prefix: p}
+}
+
+// WithPrefix returns a new Entry which prepends p to its messages after the entry's prefix, e.g. "[db] [pool] ".
+func (e *Entry) WithPrefix(p string) *Entry {
+	return &Entry{logger: e.logger, fields: copyFields(e.fields, nil), prefix: e.prefix + p, noCaller: e.noCaller}
+}
+
+// NoCaller returns an Entry which omits the caller fields, saving the cost of resolving the caller for hot paths
+// without disabling it for the whole logger with SetReportCaller. The caller is still resolved if sampling is enabled.
+func (l *Logger) NoCaller() *Entry {
+	return &Entry{logger: l, noCaller: true}
+}
+
+// NoCaller returns a new Entry which omits the caller fields. See Logger.NoCaller.
+func (e *Entry) NoCaller() *Entry {
+	return &Entry{logger: e.logger, fields: copyFields(e.fields, nil), prefix: e.prefix, noCaller: true}
+}
+
+// Tracef logs a message at level Trace.
+func (e *Entry) Tracef(format string, args ...interface{}) {
+	e.logf(logrus.TraceLevel, format, args...)
+}
+
+// Debugf logs a message at level Debug.
+func (e *Entry) Debugf(format string, args ...interface{}) {
+	e.logf(logrus.DebugLevel, format, args...)
+}
+
+// Infof logs a message at level Info.
+func (e *Entry) Infof(format string, args ...interface{}) {
+	e.logf(logrus.InfoLevel, format, args...)
+}
+
+// Warnf logs a message at level Warn.
+func (e *Entry) Warnf(format string, args ...interface{}) {
+	e.logf(logrus.WarnLevel, format, args...)
+}
+
+// Errorf logs a message at level Error.
+func (e *Entry) Errorf(format string, args ...interface{}) {
+	e.logf(logrus.ErrorLevel, format, args...)
+}
+
+// Fatalf logs a message at level Fatal and exits.
+func (e *Entry) Fatalf(format string, args ...interface{}) {
+	e.logf(logrus.FatalLevel, format, args...)
+	e.logger.exit()
+}
+
+// Panicf logs a message at level Panic and panics.
+func (e *Entry) Panicf(format string, args ...interface{}) {
+	e.logf(logrus.PanicLevel, format, args...)
+}
+
+// Trace logs a message at level Trace.
+func (e *Entry) Trace(args ...interface{}) {
+	e.logArgs(logrus.TraceLevel, args...)
+}
+
+// Debug logs a message at level Debug.
+func (e *Entry) Debug(args ...interface{}) {
+	e.logArgs(logrus.DebugLevel, args...)
+}
+
+// Info logs a message at level Info.
+func (e *Entry) Info(args ...interface{}) {
+	e.logArgs(logrus.InfoLevel, args...)
+}
+
+// Warn logs a message at level Warn.
+func (e *Entry) Warn(args ...interface{}) {
+	e.logArgs(logrus.WarnLevel, args...)
+}
+
+// Error logs a message at level Error.
+func (e *Entry) Error(args ...interface{}) {
+	e.logArgs(logrus.ErrorLevel, args...)
+}
+
+// Fatal logs a message at level Fatal and exits.
+func (e *Entry) Fatal(args ...interface{}) {
+	e.logArgs(logrus.FatalLevel, args...)
+	e.logger.exit()
+}
+
+// Panic logs a message at level Panic and panics.
+func (e *Entry) Panic(args ...interface{}) {
+	e.logArgs(logrus.PanicLevel, args...)
+}
+
+// logf logs a formatted message with the entry's fields if level is enabled. Exported helpers must call it directly
+// so that the caller frame is always skipFrameCount frames away.
+func (e *Entry) logf(level logrus.Level, format string, args ...interface{}) {
+	if !e.logger.isLevelEnabled(level) {
+		return
+	}
+
+	if entry := e.logger.newEntry(0, level, e.fields, !e.noCaller); entry != nil {
+		e.logger.write(entry, level, e.prefix+fmt.Sprintf(format, args...))
+	}
+}
+
+// logArgs logs args with the entry's fields if level is enabled. Exported helpers must call it directly so that the
+// caller frame is always skipFrameCount frames away.
+func (e *Entry) logArgs(level logrus.Level, args ...interface{}) {
+	if !e.logger.isLevelEnabled(level) {
+		return
+	}
+
+	if entry := e.logger.newEntry(0, level, e.fields, !e.noCaller); entry != nil {
+		e.logger.write(entry, level, e.prefix+fmt.Sprint(args...))
+	}
+}
+
+// errorFields returns a new map holding fields and err with its causes and stack trace if it has them.
+func (l *Logger) errorFields(fields logrus.Fields, err error) logrus.Fields {
+	if err == nil {
+		return copyFields(fields, nil)
+	}
+
+	errFields := map[string]interface{}{"error": err}
+	if causes := unwrapCauses(err, int(atomic.LoadInt32(&l.errorChainDepth))); len(causes) > 0 {
+		errFields["causes"] = causes
+	}
+	if stack := stackTrace(err); stack != "" {
+		errFields["stack"] = stack
+	}
+
+	return copyFields(fields, errFields)
+}
+
+// unwrapCauses returns the messages of at most depth errors wrapped by err. The chain is truncated where it wraps an
+// error seen before, which would loop forever otherwise.
+func unwrapCauses(err error, depth int) errorCauses {
+	var causes errorCauses
+	seen := make(map[error]bool)
+	for cause := errors.Unwrap(err); cause != nil && len(causes) < depth; cause = errors.Unwrap(cause) {
+		// Errors of uncomparable types can't be map keys, their loops are stopped by depth
+		if reflect.TypeOf(cause).Comparable() {
+			if cause == err || seen[cause] {
+				break
+			}
+			seen[cause] = true
+		}
+		causes = append(causes, cause.Error())
+	}
+
+	return causes
+}
+
+// stackTrace returns the stack trace of err if it has a StackTrace method, e.g. errors of github.com/pkg/errors. The
+// method is looked up by name to avoid depending on a particular errors package.
+func stackTrace(err error) string {
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return ""
+	}
+
+	return strings.TrimSpace(fmt.Sprintf("%+v", method.Call(nil)[0].Interface()))
+}
+
+// copyFields returns a new map holding fields of dst overridden by fields of src.
+func copyFields(dst logrus.Fields, src map[string]interface{}) logrus.Fields {
+	fields := make(logrus.Fields, len(dst)+len(src))
+	for key, value := range dst {
+		fields[key] = value
+	}
+	for key, value := range src {
+		fields[key] = value
+	}
+
+	return fields
+}
@@ -0,0 +1,268 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFieldsText(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	message := randStringBytes(30)
+	entry := l.WithFields(map[string]interface{}{"request_id": "abc-123", "user": "john doe"})
+	entry.WithFields(map[string]interface{}{"attempt": 2, "file": "spoofed.go"}).Infof("%s", message)
+
+	line := strings.TrimSpace(buf.String())
+	split := strings.Split(line, " ")
+	require.Equal(t, "INFO", split[0])
+	require.Equal(t, message, split[3])
+	require.True(t, strings.HasPrefix(split[4], "file:"))
+	require.Contains(t, split[4], "entry_test.go:")
+	require.True(t, strings.HasSuffix(line, ` attempt=2 fields.file=spoofed.go request_id=abc-123 user="john doe"`))
+
+	// Fields of the parent entry are kept as is
+	buf.Reset()
+	entry.Info(message)
+	require.NotContains(t, buf.String(), "attempt=2")
+	require.Contains(t, buf.String(), "request_id=abc-123")
+}
+
+func TestWith(t *testing.T) {
+
+	var buf syncBuffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	fields := map[string]interface{}{"request_id": "abc-123"}
+	reqLog := l.With(fields)
+	fields["request_id"] = "changed"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			reqLog.Infof("concurrent")
+		}()
+	}
+	wg.Wait()
+	reqLog.With(map[string]interface{}{"attempt": 2}).Infof("nested")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+	for _, line := range lines {
+		require.Contains(t, line, "request_id=abc-123")
+		require.Contains(t, line, "entry_test.go:")
+	}
+	require.Contains(t, lines[4], "attempt=2")
+
+	// Each line resolves its own caller
+	buf.Reset()
+	reqLog.Infof("first")
+	reqLog.Infof("second")
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.NotEqual(t, callerOf(lines[0]), callerOf(lines[1]))
+}
+
+// callerOf returns the file:line token of a text line.
+func callerOf(line string) string {
+	for _, token := range strings.Split(line, " ") {
+		if strings.HasPrefix(token, "file:") {
+			return token
+		}
+	}
+
+	return ""
+}
+
+func TestWithFieldsJSON(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false), WithFormat(FormatJSON))
+	l.SetOutput(&buf)
+
+	message := randStringBytes(30)
+	l.WithFields(map[string]interface{}{"request_id": "abc-123", "attempt": 2, "line": 0}).Errorf("%s", message)
+
+	var decoded map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &decoded)
+	require.NoError(t, err)
+
+	require.Equal(t, message, decoded["message"])
+	require.Equal(t, "abc-123", decoded["request_id"])
+	require.Equal(t, float64(2), decoded["attempt"])
+	require.Equal(t, float64(0), decoded["fields.line"])
+	require.NotZero(t, decoded["line"])
+	require.Contains(t, decoded["file"], "entry_test.go")
+}
+
+// stackError mimics errors of github.com/pkg/errors exposing a stack trace
+type stackError struct {
+	msg string
+}
+
+func (e stackError) Error() string {
+	return e.msg
+}
+
+func (e stackError) StackTrace() []string {
+	return []string{"main.main", "main.go:42"}
+}
+
+func TestWithError(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.WithError(errors.New("connection refused")).Errorf("%s", "failed")
+	require.True(t, strings.HasSuffix(strings.TrimSpace(buf.String()), ` error="connection refused"`))
+
+	// Nil errors don't add any fields
+	buf.Reset()
+	l.WithError(nil).WithFields(map[string]interface{}{"id": 1}).Errorf("%s", "failed")
+	require.NotContains(t, buf.String(), "error=")
+	require.True(t, strings.HasSuffix(strings.TrimSpace(buf.String()), ` id=1`))
+
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.WithError(stackError{msg: "timeout"}).Errorf("%s", "failed")
+
+	var decoded map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &decoded)
+	require.NoError(t, err)
+	require.Equal(t, "timeout", decoded["error"])
+	require.Equal(t, "[main.main main.go:42]", decoded["stack"])
+}
+
+// loopError wraps itself through next, forming a cycle.
+type loopError struct {
+	next *loopError
+}
+
+func (e *loopError) Error() string { return "loop" }
+func (e *loopError) Unwrap() error { return e.next }
+
+func TestWithErrorCauses(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	root := errors.New("permission denied")
+	err := fmt.Errorf("save user: %w", fmt.Errorf("open file: %w", root))
+	l.WithError(err).Errorf("%s", "failed")
+	require.True(t, strings.HasSuffix(strings.TrimSpace(buf.String()),
+		` cause1="open file: permission denied" cause2="permission denied" error="save user: open file: permission denied"`))
+
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.WithError(err).Errorf("%s", "failed")
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, []interface{}{"open file: permission denied", "permission denied"}, decoded["causes"])
+
+	// The depth caps the chain
+	buf.Reset()
+	l.SetErrorChainDepth(1)
+	l.WithError(err).Errorf("%s", "failed")
+	decoded = nil
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, []interface{}{"open file: permission denied"}, decoded["causes"])
+
+	buf.Reset()
+	l.SetErrorChainDepth(0)
+	l.WithError(err).Errorf("%s", "failed")
+	require.NotContains(t, buf.String(), "causes")
+
+	// Cycles are truncated
+	a, b := &loopError{}, &loopError{}
+	a.next, b.next = b, a
+	require.Equal(t, errorCauses{"loop"}, unwrapCauses(a, 10))
+	self := &loopError{}
+	self.next = self
+	require.Empty(t, unwrapCauses(self, 10))
+}
+
+func TestWithPrefix(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	db := l.WithPrefix("[db] ")
+	db.Infof("%s", "connected")
+	db.WithFields(map[string]interface{}{"table": "users"}).Warn("slow query")
+	db.WithPrefix("[pool] ").Error("exhausted")
+	l.Infof("%s", "unprefixed")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	require.Contains(t, lines[0], " [db] connected file:")
+	require.Contains(t, lines[0], "entry_test.go:")
+	require.Contains(t, lines[1], " [db] slow query file:")
+	require.Contains(t, lines[1], " table=users")
+	require.Contains(t, lines[2], " [db] [pool] exhausted file:")
+	require.Contains(t, lines[3], " unprefixed file:")
+
+	// The global prefix comes first
+	buf.Reset()
+	l.SetPrefix("api: ")
+	db.WithError(errors.New("timeout")).Errorf("%s", "query failed")
+	require.Contains(t, buf.String(), " api: [db] query failed file:")
+}
+
+func TestNoCaller(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.NoCaller().Infof("%s", "hot path")
+	l.NoCaller().WithFields(map[string]interface{}{"i": 1}).Info("hot path")
+	l.WithPrefix("[db] ").NoCaller().WithError(errors.New("timeout")).Error("hot path")
+	l.Infof("%s", "cold path")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	for _, line := range lines[:3] {
+		require.Contains(t, line, "hot path")
+		require.NotContains(t, line, "file:")
+		require.NotContains(t, line, "func:")
+	}
+	require.Contains(t, lines[1], " i=1")
+	require.Contains(t, lines[2], " [db] hot path")
+	require.Contains(t, lines[3], "entry_test.go:")
+}
+
+func BenchmarkInfofCaller(b *testing.B) {
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Infof("%s", "caller")
+	}
+}
+
+func BenchmarkInfofNoCaller(b *testing.B) {
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+	entry := l.NoCaller()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		entry.Infof("%s", "no caller")
+	}
+}
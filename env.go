@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Environment variables read by LoadFromEnv
+const (
+	envLogLevel      = "LOG_LEVEL"
+	envLogFile       = "LOG_FILE"
+	envLogFormat     = "LOG_FORMAT"
+	envLogMaxSizeMB  = "LOG_MAX_SIZE_MB"
+	envLogMaxBackups = "LOG_MAX_BACKUPS"
+	envLogMaxAgeDays = "LOG_MAX_AGE_DAYS"
+	envLogCompress   = "LOG_COMPRESS"
+)
+
+// LoadFromEnv applies the configuration set in the environment: LOG_LEVEL, LOG_FILE, LOG_FORMAT (text or json),
+// LOG_MAX_SIZE_MB, LOG_MAX_BACKUPS, LOG_MAX_AGE_DAYS and LOG_COMPRESS, in addition to LOG_TO_CONSOLE which is always
+// honored. Unset variables leave the configuration untouched. The values are applied when it's called, so setters
+// called afterwards take precedence and the ones called before are overridden. It can be called before or after Init,
+// which keeps the level and format, while Reinit called afterwards resets them to Info and text. Valid values are
+// applied even if others are malformed, it returns an error listing all malformed values.
+func (l *Logger) LoadFromEnv() error {
+	var errs []string
+	invalid := func(name, value string, err error) {
+		errs = append(errs, fmt.Sprintf("%s=%q: %v", name, value, err))
+	}
+
+	if value, ok := os.LookupEnv(envLogLevel); ok {
+		if err := l.SetLevel(value); err != nil {
+			invalid(envLogLevel, value, err)
+		}
+	}
+
+	if value, ok := os.LookupEnv(envLogFormat); ok {
+		switch strings.ToLower(value) {
+		case "text":
+			l.SetFormat(FormatText)
+		case "json":
+			l.SetFormat(FormatJSON)
+		default:
+			invalid(envLogFormat, value, fmt.Errorf("not text or json"))
+		}
+	}
+
+	l.mu.Lock()
+	cfg := l.rotation
+	l.mu.Unlock()
+
+	rotationSet := false
+	for name, value := range map[string]*int{
+		envLogMaxSizeMB:  &cfg.MaxSize,
+		envLogMaxBackups: &cfg.MaxBackups,
+		envLogMaxAgeDays: &cfg.MaxAge,
+	} {
+		s, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(s)
+		if err == nil && n < 0 {
+			err = fmt.Errorf("negative value")
+		}
+		if err != nil {
+			invalid(name, s, err)
+			continue
+		}
+		*value = n
+		rotationSet = true
+	}
+	if s, ok := os.LookupEnv(envLogCompress); ok {
+		compress, err := strconv.ParseBool(s)
+		if err != nil {
+			invalid(envLogCompress, s, err)
+		} else {
+			cfg.Compress = compress
+			rotationSet = true
+		}
+	}
+	if rotationSet {
+		if err := l.SetRotationConfig(cfg); err != nil {
+			invalid(envLogMaxSizeMB, strconv.Itoa(cfg.MaxSize), err)
+		}
+	}
+
+	if value, ok := os.LookupEnv(envLogFile); ok {
+		if err := l.SetLogFilePath(value); err != nil {
+			invalid(envLogFile, value, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("invalid logger environment: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// LoadFromEnv applies the configuration set in the environment to the default logger. See Logger.LoadFromEnv.
+func LoadFromEnv() error {
+	return std.LoadFromEnv()
+}
@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFromEnv(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "env")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "service.log")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_FILE", path)
+	t.Setenv("LOG_FORMAT", "JSON")
+	t.Setenv("LOG_MAX_SIZE_MB", "100")
+	t.Setenv("LOG_MAX_BACKUPS", "5")
+	t.Setenv("LOG_MAX_AGE_DAYS", "7")
+	t.Setenv("LOG_COMPRESS", "false")
+
+	l := New(WithConsole(false))
+	require.NoError(t, l.LoadFromEnv())
+	require.Equal(t, logrus.DebugLevel, l.GetLevel())
+	require.Equal(t, path, l.filePath)
+	require.Equal(t, RotationConfig{MaxSize: 100, MaxBackups: 5, MaxAge: 7}, l.rotation)
+
+	l.Debugf("%s", "message")
+	require.NoError(t, l.Close())
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"level":"DEBUG"`)
+
+	// Setters called afterwards take precedence
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+	l.SetFormat(FormatText)
+	l.Infof("%s", "message")
+	require.Contains(t, buf.String(), "INFO ")
+}
+
+func TestLoadFromEnvInvalid(t *testing.T) {
+
+	t.Setenv("LOG_LEVEL", "verbose")
+	t.Setenv("LOG_FORMAT", "xml")
+	t.Setenv("LOG_MAX_SIZE_MB", "ten")
+	t.Setenv("LOG_MAX_BACKUPS", "-1")
+	t.Setenv("LOG_MAX_AGE_DAYS", "14")
+	t.Setenv("LOG_COMPRESS", "maybe")
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+	err := l.LoadFromEnv()
+	require.Error(t, err)
+	for _, name := range []string{"LOG_LEVEL", "LOG_FORMAT", "LOG_MAX_SIZE_MB", "LOG_MAX_BACKUPS", "LOG_COMPRESS"} {
+		require.Contains(t, err.Error(), name)
+	}
+	require.NotContains(t, err.Error(), "LOG_MAX_AGE_DAYS")
+
+	// Valid values are applied anyway, malformed ones leave the configuration untouched
+	expected := defaultRotationConfig()
+	expected.MaxAge = 14
+	require.Equal(t, expected, l.rotation)
+	require.Equal(t, logrus.InfoLevel, l.GetLevel())
+}
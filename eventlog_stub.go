@@ -0,0 +1,14 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+// AddEventLogHook does nothing since the Windows Event Log is not available on this platform.
+func (l *Logger) AddEventLogHook(source string) error {
+	return nil
+}
+
+// AddEventLogHook does nothing since the Windows Event Log is not available on this platform.
+func AddEventLogHook(source string) error {
+	return std.AddEventLogHook(source)
+}
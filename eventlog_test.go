@@ -0,0 +1,18 @@
+//go:build !windows
+// +build !windows
+
+package logger
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddEventLogHookStub(t *testing.T) {
+
+	l := New(WithConsole(false))
+	require.NoError(t, l.AddEventLogHook("logger_test"))
+	require.Len(t, l.log.Hooks[logrus.ErrorLevel], 1)
+}
@@ -0,0 +1,70 @@
+//go:build windows
+// +build windows
+
+package logger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventLogID is the event id of the events written by the logger.
+const eventLogID = 1
+
+// eventLogHook writes warning and error entries to the Windows Event Log.
+type eventLogHook struct {
+	logger *Logger
+	log    *eventlog.Log
+}
+
+// Levels returns the levels the hook fires for.
+func (h *eventLogHook) Levels() []logrus.Level {
+	return levelsFrom(logrus.WarnLevel)
+}
+
+// Fire writes entry to the event log as a warning or error event.
+func (h *eventLogHook) Fire(entry *logrus.Entry) error {
+	line, err := h.logger.formatter.formatLine(entry)
+	if err != nil {
+		return err
+	}
+
+	if entry.Level == logrus.WarnLevel {
+		return h.log.Warning(eventLogID, string(line))
+	}
+
+	return h.log.Error(eventLogID, string(line))
+}
+
+// AddEventLogHook writes log lines at or above level Warn to the Windows application log under source, in addition to
+// the logger's output. Warnings are written as warning events, errors and above as error events. The source is
+// registered if it doesn't exist yet, which requires administrator rights. It returns an error if the source can't be
+// registered or opened. On other platforms it does nothing.
+func (l *Logger) AddEventLogHook(source string) error {
+	err := eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+	if err != nil && !sourceExists(err) {
+		return fmt.Errorf("failed to register event source %s: %w", source, err)
+	}
+
+	log, err := eventlog.Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open event source %s: %w", source, err)
+	}
+
+	l.AddHook(&eventLogHook{logger: l, log: log})
+
+	return nil
+}
+
+// sourceExists reports whether err of eventlog.InstallAsEventCreate is due to the source being registered already.
+func sourceExists(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "registry key already exists")
+}
+
+// AddEventLogHook writes log lines of the default logger to the Windows Event Log. See Logger.AddEventLogHook.
+func AddEventLogHook(source string) error {
+	return std.AddEventLogHook(source)
+}
@@ -0,0 +1,419 @@
+package logger
+
+import (
+	"io"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// std is the default logger used by the package level helpers.
+	std = New()
+
+	// initOnce makes Init initialize the default logger only once, initErr holds the result
+	initOnce sync.Once
+	initErr  error
+)
+
+// Init initiates the default logger with writer, formatter and level. The formatter configured before Init, e.g. the
+// format set by LoadFromEnv or the redacted keys, is kept, as is a level set by SetLevel, SetDebugLogging or
+// LoadFromEnv, otherwise it's Info. It returns an error if the log file can't be created,
+// e.g. permission denied. Init is idempotent, only the first call initializes the logger and the subsequent
+// calls return its result, so it's safe to call from multiple packages. Use Reinit to rebuild the logger.
+func Init() error {
+	initOnce.Do(func() {
+		initErr = std.init()
+	})
+
+	return initErr
+}
+
+// Reinit initiates the default logger again, e.g. after a failed Init or to start over with the default formatter,
+// level and the writer configured by LOG_TO_CONSOLE environment variable.
+func Reinit() error {
+	std.mu.Lock()
+	std.setFormatter(&formatter{})
+	std.mu.Unlock()
+
+	atomic.StoreInt32(&std.levelSet, 0)
+	return std.init()
+}
+
+// ResetForTest restores the default logger to its initial state, so that tests changing its configuration don't
+// affect each other: the default log file, rotation, formatter, Info level and output, without additional hooks,
+// sampling, deduplication or context extractors and with os.Exit as the exit function. The next Init initializes it
+// again. It's safe to call before Init, but not concurrently with logging.
+func ResetForTest() {
+	std.reset()
+	initOnce = sync.Once{}
+	initErr = nil
+}
+
+// SetLogFilePath sets the path of the default logger's log file. See Logger.SetLogFilePath.
+func SetLogFilePath(path string) error {
+	return std.SetLogFilePath(path)
+}
+
+// SetFileLogging enables or disables writing the log file of the default logger. See Logger.SetFileLogging.
+func SetFileLogging(enabled bool) {
+	std.SetFileLogging(enabled)
+}
+
+// SetStdErrThreshold sets the level at and above which console lines of the default logger are written to stderr. See
+// Logger.SetStdErrThreshold.
+func SetStdErrThreshold(level logrus.Level) {
+	std.SetStdErrThreshold(level)
+}
+
+// SetRotationConfig sets the rotation parameters of the default logger's log file. See Logger.SetRotationConfig.
+func SetRotationConfig(cfg RotationConfig) error {
+	return std.SetRotationConfig(cfg)
+}
+
+// SetReportCaller enables or disables the caller fields of the default logger. See Logger.SetReportCaller.
+func SetReportCaller(enabled bool) {
+	std.SetReportCaller(enabled)
+}
+
+// SetCallerFormat sets how the caller is rendered by the default logger. See Logger.SetCallerFormat.
+func SetCallerFormat(c CallerFormat) {
+	std.SetCallerFormat(c)
+}
+
+// SetCallerStyle sets whether the caller is rendered as separate fields or a single one by the default logger. See
+// Logger.SetCallerStyle.
+func SetCallerStyle(style CallerStyle) {
+	std.SetCallerStyle(style)
+}
+
+// SetTrimPrefixes sets prefixes trimmed from the caller file paths of the default logger. See Logger.SetTrimPrefixes.
+func SetTrimPrefixes(prefixes ...string) {
+	std.SetTrimPrefixes(prefixes...)
+}
+
+// SetSequenceField adds the field name numbering every line of the default logger. See Logger.SetSequenceField.
+func SetSequenceField(name string) {
+	std.SetSequenceField(name)
+}
+
+// SetIncludeHostPID adds the host and pid fields to every line of the default logger. See Logger.SetIncludeHostPID.
+func SetIncludeHostPID(enabled bool) {
+	std.SetIncludeHostPID(enabled)
+}
+
+// SetCallerSkip sets the number of additional stack frames to skip when resolving the caller for the default logger.
+// See Logger.SetCallerSkip.
+func SetCallerSkip(n int) {
+	std.SetCallerSkip(n)
+}
+
+// SetSampling limits each call site of the default logger to n messages per window. See Logger.SetSampling.
+func SetSampling(n int, per time.Duration) {
+	std.SetSampling(n, per)
+}
+
+// SetDedup enables or disables suppressing consecutive identical messages of the default logger. See Logger.SetDedup.
+func SetDedup(enabled bool, window time.Duration) {
+	std.SetDedup(enabled, window)
+}
+
+// SetColorized enables or disables colorizing the level of console output of the default logger. See
+// Logger.SetColorized.
+func SetColorized(enabled bool) {
+	std.SetColorized(enabled)
+}
+
+// SetForceColors makes colorized console output of the default logger be written even if stdout is not a terminal.
+func SetForceColors(force bool) {
+	std.SetForceColors(force)
+}
+
+// SetAsync makes the output of the default logger be written by a background goroutine. See Logger.SetAsync.
+func SetAsync(bufferSize int, opts ...AsyncOption) {
+	std.SetAsync(bufferSize, opts...)
+}
+
+// AsyncDropped returns the number of lines dropped by asynchronous writing of the default logger. See
+// Logger.AsyncDropped.
+func AsyncDropped() uint64 {
+	return std.AsyncDropped()
+}
+
+// SetPrefix prepends prefix s to the log messages and call it thread safe.
+func SetPrefix(s string) {
+	std.SetPrefix(s)
+}
+
+// SetFormat sets the encoding of the log lines of the default logger, FormatText by default.
+func SetFormat(format Format) {
+	std.SetFormat(format)
+}
+
+// SetVersion sets the application version rendered in log lines of the default logger. See Logger.SetVersion.
+func SetVersion(v string) {
+	std.SetVersion(v)
+}
+
+// SetFormatter replaces the built-in layouts of the default logger with f. See Logger.SetFormatter.
+func SetFormatter(f logrus.Formatter) {
+	std.SetFormatter(f)
+}
+
+// DefaultFormatter returns the built-in formatter of the default logger. See Logger.DefaultFormatter.
+func DefaultFormatter() logrus.Formatter {
+	return std.DefaultFormatter()
+}
+
+// SetLineSeparator sets the separator terminating every line of the default logger. See Logger.SetLineSeparator.
+func SetLineSeparator(sep string) {
+	std.SetLineSeparator(sep)
+}
+
+// SetLevelNames sets the level tokens rendered by the default logger. See Logger.SetLevelNames.
+func SetLevelNames(names map[logrus.Level]string) {
+	std.SetLevelNames(names)
+}
+
+// SetFieldOrder sets the segments of the text layout of the default logger in order. See Logger.SetFieldOrder.
+func SetFieldOrder(order []string) error {
+	return std.SetFieldOrder(order)
+}
+
+// SetJSONFieldNames renames the fields of the JSON layout of the default logger. See Logger.SetJSONFieldNames.
+func SetJSONFieldNames(names map[string]string) error {
+	return std.SetJSONFieldNames(names)
+}
+
+// SetRedactedKeys masks the values of the fields with keys for the default logger. See Logger.SetRedactedKeys.
+func SetRedactedKeys(keys ...string) {
+	std.SetRedactedKeys(keys...)
+}
+
+// SetRedactPattern masks the matches of re in messages of the default logger. See Logger.SetRedactPattern.
+func SetRedactPattern(re *regexp.Regexp) {
+	std.SetRedactPattern(re)
+}
+
+// SetMaxMessageLength truncates messages of the default logger longer than n runes. See Logger.SetMaxMessageLength.
+func SetMaxMessageLength(n int) {
+	std.SetMaxMessageLength(n)
+}
+
+// SetEscapeControlChars enables or disables escaping the control characters in the text layout of the default logger.
+// See Logger.SetEscapeControlChars.
+func SetEscapeControlChars(enabled bool) {
+	std.SetEscapeControlChars(enabled)
+}
+
+// SetFileFormat sets the encoding of the lines written to the log file of the default logger. See
+// Logger.SetFileFormat.
+func SetFileFormat(format Format) {
+	std.SetFileFormat(format)
+}
+
+// SetConsoleFormat sets the encoding of the lines written to the console by the default logger. See
+// Logger.SetConsoleFormat.
+func SetConsoleFormat(format Format) {
+	std.SetConsoleFormat(format)
+}
+
+// SetTimeFormat sets the layout of the timestamps of the default logger. See Logger.SetTimeFormat.
+func SetTimeFormat(layout string) {
+	std.SetTimeFormat(layout)
+}
+
+// SetTimePrecision sets the fraction of a second rendered in the timestamps of the default logger. See
+// Logger.SetTimePrecision.
+func SetTimePrecision(p TimePrecision) {
+	std.SetTimePrecision(p)
+}
+
+// SetReportVersion enables or disables the version of the log lines of the default logger at levels. See
+// Logger.SetReportVersion.
+func SetReportVersion(enabled bool, levels ...logrus.Level) {
+	std.SetReportVersion(enabled, levels...)
+}
+
+// SetReportTimestamp enables or disables the time of the log lines of the default logger. See
+// Logger.SetReportTimestamp.
+func SetReportTimestamp(enabled bool) {
+	std.SetReportTimestamp(enabled)
+}
+
+// SetTimeZone sets the location the timestamps of the default logger are rendered in. See Logger.SetTimeZone.
+func SetTimeZone(loc *time.Location) error {
+	return std.SetTimeZone(loc)
+}
+
+// WithFields returns an Entry of the default logger which attaches fields to its messages. See Logger.WithFields.
+func WithFields(fields map[string]interface{}) *Entry {
+	return std.WithFields(fields)
+}
+
+// With returns a reusable Entry of the default logger which attaches fields to its messages. See Logger.With.
+func With(fields map[string]interface{}) *Entry {
+	return std.With(fields)
+}
+
+// WithError returns an Entry of the default logger which attaches err to its messages. See Logger.WithError.
+func WithError(err error) *Entry {
+	return std.WithError(err)
+}
+
+// SetErrorChainDepth sets how many wrapped errors WithError attaches as causes on the default logger. See
+// Logger.SetErrorChainDepth.
+func SetErrorChainDepth(depth int) {
+	std.SetErrorChainDepth(depth)
+}
+
+// WithPrefix returns an Entry of the default logger which prepends p to its messages. See Logger.WithPrefix.
+func WithPrefix(p string) *Entry {
+	return std.WithPrefix(p)
+}
+
+// NoCaller returns an Entry of the default logger which omits the caller fields. See Logger.NoCaller.
+func NoCaller() *Entry {
+	return std.NoCaller()
+}
+
+// Tracef logs a message at level Trace on the standard logger.
+func Tracef(format string, args ...interface{}) {
+	std.logf(logrus.TraceLevel, format, args...)
+}
+
+// Debugf logs a message at level Debug on the standard logger.
+func Debugf(format string, args ...interface{}) {
+	std.logf(logrus.DebugLevel, format, args...)
+}
+
+// Infof logs a message at level Info on the standard logger.
+func Infof(format string, args ...interface{}) {
+	std.logf(logrus.InfoLevel, format, args...)
+}
+
+// Warnf logs a message at level Warn on the standard logger.
+func Warnf(format string, args ...interface{}) {
+	std.logf(logrus.WarnLevel, format, args...)
+}
+
+// Errorf logs a message at level Error on the standard logger.
+func Errorf(format string, args ...interface{}) {
+	std.logf(logrus.ErrorLevel, format, args...)
+}
+
+// Fatalf logs a message at level Fatal on the standard logger.
+func Fatalf(format string, args ...interface{}) {
+	std.logf(logrus.FatalLevel, format, args...)
+	std.exit()
+}
+
+// Panicf logs a message at level Panic on the standard logger and panics.
+func Panicf(format string, args ...interface{}) {
+	std.logf(logrus.PanicLevel, format, args...)
+}
+
+// Trace logs a message at level Trace on the standard logger.
+func Trace(args ...interface{}) {
+	std.logArgs(logrus.TraceLevel, args...)
+}
+
+// Debug logs a message at level Debug on the standard logger.
+func Debug(args ...interface{}) {
+	std.logArgs(logrus.DebugLevel, args...)
+}
+
+// Info logs a message at level Info on the standard logger.
+func Info(args ...interface{}) {
+	std.logArgs(logrus.InfoLevel, args...)
+}
+
+// Warn logs a message at level Warn on the standard logger.
+func Warn(args ...interface{}) {
+	std.logArgs(logrus.WarnLevel, args...)
+}
+
+// Error logs a message at level Error on the standard logger.
+func Error(args ...interface{}) {
+	std.logArgs(logrus.ErrorLevel, args...)
+}
+
+// Fatal logs a message at level Fatal on the standard logger.
+func Fatal(args ...interface{}) {
+	std.logArgs(logrus.FatalLevel, args...)
+	std.exit()
+}
+
+// Panic logs a message at level Panic on the standard logger and panics.
+func Panic(args ...interface{}) {
+	std.logArgs(logrus.PanicLevel, args...)
+}
+
+// Writer returns the underlying io.Writer instance of the logger.
+func Writer() io.Writer {
+	return std.Writer()
+}
+
+// SetOutput makes the default logger write to w exactly. See Logger.SetOutput.
+func SetOutput(w io.Writer) {
+	std.SetOutput(w)
+}
+
+// ResetOutput restores the default output of the default logger. See Logger.ResetOutput.
+func ResetOutput() {
+	std.ResetOutput()
+}
+
+// Flush flushes writes buffered by the output of the default logger. See Logger.Flush.
+func Flush() error {
+	return std.Flush()
+}
+
+// Close flushes the output and closes the log file of the default logger. See Logger.Close.
+func Close() error {
+	return std.Close()
+}
+
+// SetExitFunc sets the function called by the Fatal helpers of the default logger. See Logger.SetExitFunc.
+func SetExitFunc(fn func(int)) {
+	std.SetExitFunc(fn)
+}
+
+// SetDebugLogging sets the logging level
+func SetDebugLogging(enabled bool) {
+	std.SetDebugLogging(enabled)
+}
+
+// SetLevel parses level and sets it as the logging level of the default logger. See Logger.SetLevel.
+func SetLevel(level string) error {
+	return std.SetLevel(level)
+}
+
+// SetLevelL sets the logging level of the default logger.
+func SetLevelL(level logrus.Level) {
+	std.SetLevelL(level)
+}
+
+// GetLevel returns the logger instance's log level and exported for testing purposes to determine log level is set
+// correctly.
+func GetLevel() logrus.Level {
+	return std.GetLevel()
+}
+
+// LevelString returns the name of the log level of the default logger, e.g. "info".
+func LevelString() string {
+	return std.LevelString()
+}
+
+// IsDebugEnabled reports whether Debug messages are logged by the default logger.
+func IsDebugEnabled() bool {
+	return std.IsDebugEnabled()
+}
+
+// IsTraceEnabled reports whether Trace messages are logged by the default logger.
+func IsTraceEnabled() bool {
+	return std.IsTraceEnabled()
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FatalfFields logs a message at level Fatal with fields and exits through the function set by SetExitFunc. Before
+// exiting, buffered and asynchronous writes are flushed and the log file is synced to disk so that the message isn't
+// lost, which all Fatal helpers do.
+func (l *Logger) FatalfFields(fields map[string]interface{}, format string, args ...interface{}) {
+	l.logfFields(logrus.FatalLevel, fields, format, args...)
+	l.exit()
+}
+
+// logfFields formats and logs a message at level with fields if level is enabled. Exported helpers must call it
+// directly so that the caller frame is always skipFrameCount frames away.
+func (l *Logger) logfFields(level logrus.Level, fields map[string]interface{}, format string, args ...interface{}) {
+	if !l.isLevelEnabled(level) {
+		return
+	}
+
+	if entry := l.newEntry(0, level, fields, true); entry != nil {
+		l.write(entry, level, fmt.Sprintf(format, args...))
+	}
+}
+
+// exit flushes the output and syncs the log file so that the fatal message isn't lost and exits with status 1 through
+// logrus' ExitFunc.
+func (l *Logger) exit() {
+	l.mu.Lock()
+	_ = l.flush()
+	_ = l.syncFile()
+	l.mu.Unlock()
+
+	l.log.Exit(1)
+}
+
+// syncFile commits the written lines of the log file to disk, callers must hold l.mu. The file handle of lumberjack
+// isn't exposed, a separate handle is synced instead which commits the same file.
+func (l *Logger) syncFile() error {
+	if l.output != nil || l.noFile {
+		return nil
+	}
+	if l.dailyFile != nil {
+		return l.dailyFile.Sync()
+	}
+
+	f, err := os.OpenFile(l.filePath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		// Nothing was written yet
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	return f.Sync()
+}
+
+// FatalfFields logs a message at level Fatal with fields on the standard logger and exits. See Logger.FatalfFields.
+func FatalfFields(fields map[string]interface{}, format string, args ...interface{}) {
+	std.logfFields(logrus.FatalLevel, fields, format, args...)
+	std.exit()
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFatalfFields(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_fatal_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	l := New(WithConsole(false), WithFilePath(path))
+	defer l.Close()
+	l.SetAsync(16)
+
+	// The message must be in the file by the time the process would exit
+	var content []byte
+	var exitCode int
+	l.SetExitFunc(func(code int) {
+		exitCode = code
+		content, err = ioutil.ReadFile(path)
+	})
+
+	l.FatalfFields(map[string]interface{}{"component": "db"}, "failed to %s", "connect")
+
+	require.Equal(t, 1, exitCode)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "failed to connect")
+	require.Contains(t, string(content), "component=db")
+	require.Contains(t, string(content), "fatal_test.go")
+}
+
+func TestFatalfFieldsDaily(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_fatal_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l := New(WithConsole(false), WithFilePath(filepath.Join(dir, "app.log")))
+	defer l.Close()
+	require.NoError(t, l.RotateDaily(true))
+
+	exited := false
+	l.SetExitFunc(func(int) { exited = true })
+
+	l.FatalfFields(nil, "shutting down")
+
+	require.True(t, exited)
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+}
@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// AddFilter adds a filter deciding whether an entry is logged, returning false drops it, e.g. to skip the entries of a
+// noisy component by a field. The entry passed to filter carries the level, message and fields, including the caller.
+// An entry is logged only if all filters keep it. Fatal and panic entries are always logged, so that they still exit or
+// panic. Filters run before the entry is formatted and handed to the output and hooks, so they must be cheap and must
+// not log through the logger.
+func (l *Logger) AddFilter(filter func(*logrus.Entry) bool) {
+	l.filtersMu.Lock()
+	defer l.filtersMu.Unlock()
+
+	filters, _ := l.filters.Load().([]func(*logrus.Entry) bool)
+	// Copied so that entries being filtered keep a consistent view
+	filters = append(filters[:len(filters):len(filters)], filter)
+	l.filters.Store(filters)
+}
+
+// filtered reports whether entry with level and msg is dropped by a filter. Fatal and panic entries never are.
+func (l *Logger) filtered(entry *logrus.Entry, level logrus.Level, msg string) bool {
+	filters, _ := l.filters.Load().([]func(*logrus.Entry) bool)
+	if len(filters) == 0 || level <= logrus.FatalLevel {
+		return false
+	}
+
+	entry.Level = level
+	entry.Message = msg
+	for _, filter := range filters {
+		if !filter(entry) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddFilter adds a filter deciding whether an entry of the default logger is logged. See Logger.AddFilter.
+func AddFilter(filter func(*logrus.Entry) bool) {
+	std.AddFilter(filter)
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddFilter(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.AddFilter(func(entry *logrus.Entry) bool {
+		return entry.Data["component"] != "healthcheck"
+	})
+
+	l.WithFields(map[string]interface{}{"component": "healthcheck"}).Infof("probe")
+	l.WithFields(map[string]interface{}{"component": "api"}).Infof("request")
+	l.Infof("startup")
+	require.NotContains(t, buf.String(), "probe")
+	require.Contains(t, buf.String(), "request")
+	require.Contains(t, buf.String(), "startup")
+
+	// Filters compose, all of them must keep an entry
+	l.AddFilter(func(entry *logrus.Entry) bool {
+		return entry.Level <= logrus.WarnLevel || entry.Message != "noise"
+	})
+
+	buf.Reset()
+	l.Infof("noise")
+	l.Warnf("noise")
+	l.WithFields(map[string]interface{}{"component": "healthcheck"}).Warnf("probe failed")
+	require.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("noise")))
+	require.Contains(t, buf.String(), "WARNING")
+	require.NotContains(t, buf.String(), "probe failed")
+
+	// Panic entries bypass the filters so that they still panic
+	buf.Reset()
+	l.AddFilter(func(*logrus.Entry) bool {
+		return false
+	})
+	l.Infof("dropped")
+	require.Panics(t, func() {
+		l.Panicf("unrecoverable")
+	})
+	require.NotContains(t, buf.String(), "dropped")
+	require.Contains(t, buf.String(), "unrecoverable")
+}
@@ -0,0 +1,914 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// redactedValue replaces redacted field values and message parts.
+const redactedValue = "***"
+
+// defaultLevelNames are the level tokens rendered in log lines unless overridden by SetLevelNames. The warning level
+// is rendered as WARNING.
+var defaultLevelNames = map[logrus.Level]string{
+	logrus.PanicLevel: "PANIC",
+	logrus.FatalLevel: "FATAL",
+	logrus.ErrorLevel: "ERROR",
+	logrus.WarnLevel:  "WARNING",
+	logrus.InfoLevel:  "INFO",
+	logrus.DebugLevel: "DEBUG",
+	logrus.TraceLevel: "TRACE",
+}
+
+// Format is the encoding of the log lines.
+type Format int
+
+const (
+	// FormatText renders log lines in the space delimited text layout.
+	FormatText Format = iota
+	// FormatJSON renders each log line as a JSON object.
+	FormatJSON
+)
+
+// CallerFormat is how the caller is rendered in log lines.
+type CallerFormat int
+
+const (
+	// CallerFull renders the file path as trimmed by the logger and the full function name, e.g.
+	// file:github.com/acme/app/db/pool.go:42 func:github.com/acme/app/db.(*Pool).Get. It's the default.
+	CallerFull CallerFormat = iota
+	// CallerShort renders the base name of the file and the function name with its package name only, e.g.
+	// file:pool.go:42 func:db.(*Pool).Get.
+	CallerShort
+	// CallerNone omits the caller.
+	CallerNone
+)
+
+// CallerStyle is whether the caller is rendered as separate file, line and function fields or a single one.
+type CallerStyle int
+
+const (
+	// CallerSeparate renders the file with the line and the function separately, e.g. file:pool.go:42 func:db.Get in
+	// text and "file", "line" and "function" in JSON. It's the default.
+	CallerSeparate CallerStyle = iota
+	// CallerCombined renders the file and the line as a single caller field without the function, e.g.
+	// caller:db/pool.go:42 in text and "caller":"db/pool.go:42" in JSON, as expected by tools keyed on caller.
+	CallerCombined
+)
+
+// Segments of the text layout which can be reordered with SetFieldOrder.
+const (
+	FieldLevel   = "level"
+	FieldTime    = "time"
+	FieldVersion = "version"
+	FieldPrefix  = "prefix"
+	FieldMessage = "message"
+	FieldCaller  = "caller"
+)
+
+// textFields are the segments of the text layout.
+var textFields = map[string]bool{
+	FieldLevel:   true,
+	FieldTime:    true,
+	FieldVersion: true,
+	FieldPrefix:  true,
+	FieldMessage: true,
+	FieldCaller:  true,
+}
+
+// jsonKeys are the keys of the fields rendered by the JSON layout itself, which SetJSONFieldNames can rename.
+var jsonKeys = map[string]bool{
+	"level":    true,
+	"time":     true,
+	"version":  true,
+	"prefix":   true,
+	"message":  true,
+	"caller":   true,
+	"file":     true,
+	"line":     true,
+	"function": true,
+}
+
+// TimePrecision is the fraction of a second rendered in the default RFC3339 timestamps.
+type TimePrecision int
+
+const (
+	// PrecisionMillisecond renders milliseconds, e.g. 2021-01-26T14:37:17.123+03:00. It's the default.
+	PrecisionMillisecond TimePrecision = iota
+	// PrecisionSecond renders whole seconds, e.g. 2021-01-26T14:37:17+03:00.
+	PrecisionSecond
+	// PrecisionMicrosecond renders microseconds, e.g. 2021-01-26T14:37:17.123456+03:00.
+	PrecisionMicrosecond
+	// PrecisionNanosecond renders nanoseconds, e.g. 2021-01-26T14:37:17.123456789+03:00.
+	PrecisionNanosecond
+)
+
+// layout returns the RFC3339 layout with the fraction of a second of the precision. The fraction is zero padded so
+// that the timestamps have a fixed width.
+func (p TimePrecision) layout() string {
+	switch p {
+	case PrecisionSecond:
+		return time.RFC3339
+	case PrecisionMicrosecond:
+		return "2006-01-02T15:04:05.000000Z07:00"
+	case PrecisionNanosecond:
+		return "2006-01-02T15:04:05.000000000Z07:00"
+	default:
+		return "2006-01-02T15:04:05.000Z07:00"
+	}
+}
+
+// formatOverride is a format set for a single destination.
+type formatOverride struct {
+	format Format
+	set    bool
+}
+
+// or returns the overriding format if set, def otherwise.
+func (o formatOverride) or(def Format) Format {
+	if o.set {
+		return o.format
+	}
+
+	return def
+}
+
+// Formatter implements logrus.Formatter interface.
+type formatter struct {
+	// mu guards the configuration below which can be changed while logging
+	mu         sync.RWMutex
+	prefix     string
+	format     Format
+	timeFormat string
+	precision  TimePrecision
+	caller     CallerFormat
+	style      CallerStyle
+	location   *time.Location
+	version    string
+
+	// noTimestamp omits the time, e.g. if the collector stamps the lines
+	noTimestamp bool
+
+	// noVersion holds the levels whose lines omit the version, replaced rather than modified
+	noVersion map[logrus.Level]bool
+
+	// maxMessageLength truncates messages to that many runes if positive
+	maxMessageLength int
+
+	// escapeControl escapes the control characters of messages and field keys in the text layout
+	escapeControl bool
+
+	// fileFormat and consoleFormat override format for a single destination if set
+	fileFormat    formatOverride
+	consoleFormat formatOverride
+
+	redactedKeys  map[string]bool
+	redactPattern *regexp.Regexp
+
+	// levelNames override defaultLevelNames
+	levelNames map[logrus.Level]string
+
+	// lineSeparator overrides the line separator of the platform if lineSeparatorSet
+	lineSeparator    string
+	lineSeparatorSet bool
+
+	// custom replaces the built-in layouts if set
+	custom logrus.Formatter
+
+	// fieldOrder lists the segments of the text layout in order, nil for the default layout
+	fieldOrder []string
+
+	// jsonNames maps the keys of the JSON layout to the names rendered instead, replaced rather than modified
+	jsonNames map[string]string
+}
+
+// setPrefix sets the prefix prepended to the messages.
+func (f *formatter) setPrefix(s string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.prefix = s
+}
+
+// setFormat sets the encoding of the log lines.
+func (f *formatter) setFormat(format Format) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.format = format
+}
+
+// setFileFormat sets the encoding of the lines written to the output, i.e. the log file, overriding format.
+func (f *formatter) setFileFormat(format Format) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fileFormat = formatOverride{format: format, set: true}
+}
+
+// setConsoleFormat sets the encoding of the lines written to the console, overriding format.
+func (f *formatter) setConsoleFormat(format Format) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.consoleFormat = formatOverride{format: format, set: true}
+}
+
+// setTimeFormat sets the layout of the timestamps, an empty layout restores the default RFC3339 with the configured
+// precision.
+func (f *formatter) setTimeFormat(layout string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.timeFormat = layout
+}
+
+// setCallerFormat sets how the caller is rendered.
+func (f *formatter) setCallerFormat(c CallerFormat) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.caller = c
+}
+
+// setCallerStyle sets whether the caller is rendered as separate fields or a single one.
+func (f *formatter) setCallerStyle(style CallerStyle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.style = style
+}
+
+// setTimePrecision sets the fraction of a second rendered in the default RFC3339 timestamps.
+func (f *formatter) setTimePrecision(p TimePrecision) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.precision = p
+}
+
+// setTimeZone sets the location the timestamps are rendered in.
+func (f *formatter) setTimeZone(loc *time.Location) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.location = loc
+}
+
+// setReportTimestamp enables or disables rendering the time.
+func (f *formatter) setReportTimestamp(enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.noTimestamp = !enabled
+}
+
+// setReportVersion enables or disables rendering the version at levels, at all levels if none are given.
+func (f *formatter) setReportVersion(enabled bool, levels ...logrus.Level) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+
+	noVersion := make(map[logrus.Level]bool, len(f.noVersion)+len(levels))
+	for level := range f.noVersion {
+		noVersion[level] = true
+	}
+	for _, level := range levels {
+		if enabled {
+			delete(noVersion, level)
+		} else {
+			noVersion[level] = true
+		}
+	}
+	if len(noVersion) == 0 {
+		noVersion = nil
+	}
+	f.noVersion = noVersion
+}
+
+// setMaxMessageLength sets the number of runes messages are truncated to, zero or less disables truncation.
+func (f *formatter) setMaxMessageLength(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.maxMessageLength = n
+}
+
+// setEscapeControlChars enables or disables escaping the control characters of the text layout.
+func (f *formatter) setEscapeControlChars(enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.escapeControl = enabled
+}
+
+// setVersion sets the version rendered in log lines, an empty version restores the build time default.
+func (f *formatter) setVersion(v string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.version = v
+}
+
+// setRedactedKeys sets the keys of the fields whose values are masked, matched case-insensitively.
+func (f *formatter) setRedactedKeys(keys ...string) {
+	redactedKeys := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		redactedKeys[strings.ToLower(key)] = true
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.redactedKeys = redactedKeys
+}
+
+// setRedactPattern sets the pattern whose matches are masked in messages.
+func (f *formatter) setRedactPattern(re *regexp.Regexp) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.redactPattern = re
+}
+
+// setLevelNames sets the tokens rendered for the levels in names, the other levels keep their default token.
+func (f *formatter) setLevelNames(names map[logrus.Level]string) {
+	levelNames := make(map[logrus.Level]string, len(names))
+	for level, name := range names {
+		levelNames[level] = name
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.levelNames = levelNames
+}
+
+// setLineSeparator sets the separator terminating every line, overriding the line separator of the platform.
+func (f *formatter) setLineSeparator(sep string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lineSeparator = sep
+	f.lineSeparatorSet = true
+}
+
+// setFieldOrder sets the segments of the text layout in order, nil restores the default layout.
+func (f *formatter) setFieldOrder(order []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fieldOrder = order
+}
+
+// setJSONFieldNames sets the names rendered instead of the keys of the JSON layout, nil restores the keys.
+func (f *formatter) setJSONFieldNames(names map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.jsonNames = names
+}
+
+// jsonName returns the name rendered for key of the JSON layout. Callers must hold f.mu.
+func (f *formatter) jsonName(key string) string {
+	if name, ok := f.jsonNames[key]; ok {
+		return name
+	}
+
+	return key
+}
+
+// jsonCustomKey returns the name rendered for the custom field key in JSON, prefixed with "fields." if a key of the
+// layout is renamed to it. Callers must hold f.mu.
+func (f *formatter) jsonCustomKey(key string) string {
+	for _, name := range f.jsonNames {
+		if name == key {
+			return "fields." + key
+		}
+	}
+
+	return key
+}
+
+// setCustom sets a formatter replacing the built-in layouts, nil restores them.
+func (f *formatter) setCustom(custom logrus.Formatter) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.custom = custom
+}
+
+// customFormatter returns the formatter replacing the built-in layouts, nil if there is none. It's called without
+// holding f.mu while formatting, custom formatters may delegate to the built-in one.
+func (f *formatter) customFormatter() logrus.Formatter {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.custom
+}
+
+// Format building log message of the log file. Tagged entries are skipped since the tag hook writes them to the file of
+// their tag instead.
+func (f *formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if _, ok := entry.Data[tagKey].(tagName); ok {
+		return nil, nil
+	}
+
+	return f.formatLine(entry)
+}
+
+// formatLine renders entry as a line of the log file, with the custom formatter if set, e.g. for hooks writing files.
+func (f *formatter) formatLine(entry *logrus.Entry) ([]byte, error) {
+	if custom := f.customFormatter(); custom != nil {
+		return custom.Format(entry)
+	}
+
+	return f.formatBuiltin(entry)
+}
+
+// formatBuiltin renders entry in the built-in layout of the file, ignoring a custom formatter.
+func (f *formatter) formatBuiltin(entry *logrus.Entry) ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.fileFormat.or(f.format) == FormatJSON {
+		return f.formatJSON(entry)
+	}
+
+	return f.formatText(entry), nil
+}
+
+// builtinFormatter renders entries in the built-in layout of a logger, ignoring its custom formatter.
+type builtinFormatter struct {
+	logger *Logger
+}
+
+// Format renders entry in the built-in layout of the log file.
+func (b builtinFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return b.logger.formatter.formatBuiltin(entry)
+}
+
+// formatAs renders entry in format regardless of the configured one, e.g. for hooks requiring JSON.
+func (f *formatter) formatAs(entry *logrus.Entry, format Format) ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if format == FormatJSON {
+		return f.formatJSON(entry)
+	}
+
+	return f.formatText(entry), nil
+}
+
+// formatConsole renders entry for the console, colorizing the level in text format if colorized is set. A custom
+// formatter is used as is.
+func (f *formatter) formatConsole(entry *logrus.Entry, colorized bool) ([]byte, error) {
+	if custom := f.customFormatter(); custom != nil {
+		return custom.Format(entry)
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.consoleFormat.or(f.format) == FormatJSON {
+		return f.formatJSON(entry)
+	}
+
+	return f.formatTextColorized(entry, colorized), nil
+}
+
+// formatText renders entry in the space delimited text layout.
+func (f *formatter) formatText(entry *logrus.Entry) []byte {
+	return f.formatTextColorized(entry, false)
+}
+
+// formatTextColorized renders entry in the space delimited text layout, wrapping the level in ANSI color codes if
+// colorized is set.
+func (f *formatter) formatTextColorized(entry *logrus.Entry, colorized bool) []byte {
+	var sb bytes.Buffer
+
+	level := f.levelName(entry.Level)
+	if colorized {
+		level = levelColor(entry.Level) + level + colorReset
+	}
+	if f.fieldOrder != nil {
+		f.writeOrderedText(&sb, entry, level)
+	} else {
+		sb.WriteString(level)
+		sb.WriteString(" ")
+		if !f.noTimestamp {
+			sb.WriteString(f.formatTime(entry.Time))
+			sb.WriteString(" ")
+		}
+		if !f.noVersion[entry.Level] {
+			sb.WriteString(f.appVersion())
+			sb.WriteString(" ")
+		}
+		sb.WriteString(f.prefix)
+		sb.WriteString(f.textMessage(entry))
+		sb.WriteString(" ")
+		f.writeTextCaller(&sb, entry)
+	}
+	for _, key := range customKeys(entry) {
+		value := f.fieldValue(key, entry.Data[key])
+		if causes, ok := value.(errorCauses); ok {
+			for i, cause := range causes {
+				sb.WriteString(" cause")
+				sb.WriteString(strconv.Itoa(i + 1))
+				sb.WriteString("=")
+				sb.WriteString(textValue(cause))
+			}
+			continue
+		}
+
+		sb.WriteString(" ")
+		sb.WriteString(f.textKey(key))
+		sb.WriteString("=")
+		sb.WriteString(textValue(value))
+	}
+	sb.WriteString(f.newLine())
+
+	return sb.Bytes()
+}
+
+// writeOrderedText writes the segments of the text layout in the configured order separated by spaces. The prefix is
+// written as is without a separator since it's usually terminated by its own, segments without content are skipped.
+func (f *formatter) writeOrderedText(sb *bytes.Buffer, entry *logrus.Entry, level string) {
+	separate := false
+	for _, field := range f.fieldOrder {
+		var segment bytes.Buffer
+		switch field {
+		case FieldLevel:
+			segment.WriteString(level)
+		case FieldTime:
+			if !f.noTimestamp {
+				segment.WriteString(f.formatTime(entry.Time))
+			}
+		case FieldVersion:
+			if !f.noVersion[entry.Level] {
+				segment.WriteString(f.appVersion())
+			}
+		case FieldPrefix:
+			segment.WriteString(f.prefix)
+		case FieldMessage:
+			segment.WriteString(f.textMessage(entry))
+		case FieldCaller:
+			f.writeTextCaller(&segment, entry)
+		}
+		if segment.Len() == 0 && field != FieldMessage {
+			continue
+		}
+
+		if separate {
+			sb.WriteString(" ")
+		}
+		sb.Write(segment.Bytes())
+		separate = field != FieldPrefix
+	}
+}
+
+// writeTextCaller writes the caller of entry as "file:<file>:<line> func:<function>", or "caller:<file>:<line>" in
+// CallerCombined style, unless it's omitted.
+func (f *formatter) writeTextCaller(sb *bytes.Buffer, entry *logrus.Entry) {
+	if f.caller == CallerNone {
+		return
+	}
+	if f.style == CallerCombined {
+		if caller, ok := f.combinedCaller(entry); ok {
+			sb.WriteString("caller:")
+			sb.WriteString(caller)
+		}
+		return
+	}
+
+	file, ok := entry.Data["file"].(string)
+	if ok {
+		sb.WriteString("file:")
+		sb.WriteString(f.callerValue("file", file).(string))
+	}
+	line, ok := entry.Data["line"].(int)
+	if ok {
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(line))
+	}
+	function, ok := entry.Data["function"].(string)
+	if ok {
+		sb.WriteString(" ")
+		sb.WriteString("func:")
+		sb.WriteString(f.callerValue("function", function).(string))
+	}
+}
+
+// formatJSON renders entry as a JSON object. Caller fields are omitted if the entry doesn't carry them.
+func (f *formatter) formatJSON(entry *logrus.Entry) ([]byte, error) {
+	var sb bytes.Buffer
+
+	fields := []jsonField{{f.jsonName("level"), f.levelName(entry.Level)}}
+	if !f.noTimestamp {
+		fields = append(fields, jsonField{f.jsonName("time"), f.formatTime(entry.Time)})
+	}
+	if !f.noVersion[entry.Level] {
+		fields = append(fields, jsonField{f.jsonName("version"), f.appVersion()})
+	}
+	if f.prefix != "" {
+		fields = append(fields, jsonField{f.jsonName("prefix"), f.prefix})
+	}
+	fields = append(fields, jsonField{f.jsonName("message"), f.message(entry)})
+	if f.caller != CallerNone && f.style == CallerCombined {
+		if caller, ok := f.combinedCaller(entry); ok {
+			fields = append(fields, jsonField{f.jsonName("caller"), caller})
+		}
+	} else if f.caller != CallerNone {
+		for _, key := range callerKeys {
+			if value, ok := entry.Data[key]; ok {
+				fields = append(fields, jsonField{f.jsonName(key), f.callerValue(key, value)})
+			}
+		}
+	}
+	for _, key := range customKeys(entry) {
+		fields = append(fields, jsonField{f.jsonCustomKey(key), f.fieldValue(key, entry.Data[key])})
+	}
+
+	sb.WriteString("{")
+	for _, field := range fields {
+		if err := writeJSONField(&sb, field.key, field.value); err != nil {
+			return nil, err
+		}
+	}
+	sb.WriteString("}")
+	sb.WriteString(f.newLine())
+
+	return sb.Bytes(), nil
+}
+
+// levelName returns the token rendered for level.
+func (f *formatter) levelName(level logrus.Level) string {
+	if name, ok := f.levelNames[level]; ok {
+		return name
+	}
+	if name, ok := defaultLevelNames[level]; ok {
+		return name
+	}
+
+	return strings.ToUpper(level.String())
+}
+
+// formatTime renders t with the configured layout and location, defaulting to RFC3339 with the configured precision
+// in local time.
+func (f *formatter) formatTime(t time.Time) string {
+	if f.location != nil {
+		t = t.In(f.location)
+	}
+
+	layout := f.timeFormat
+	if layout == "" {
+		layout = f.precision.layout()
+	}
+
+	return t.Format(layout)
+}
+
+// message returns the message of entry with the matches of the redact pattern masked, truncated to the maximum length.
+func (f *formatter) message(entry *logrus.Entry) string {
+	msg := entry.Message
+	if f.redactPattern != nil {
+		msg = f.redactPattern.ReplaceAllString(msg, redactedValue)
+	}
+
+	return truncateMessage(msg, f.maxMessageLength)
+}
+
+// textMessage returns the message of entry for the text layout, with its control characters escaped if enabled.
+func (f *formatter) textMessage(entry *logrus.Entry) string {
+	if f.escapeControl {
+		return escapeControlChars(f.message(entry))
+	}
+
+	return f.message(entry)
+}
+
+// textKey returns key of a custom field for the text layout, with its control characters escaped if enabled. Values
+// are quoted and escaped anyway.
+func (f *formatter) textKey(key string) string {
+	if f.escapeControl {
+		return escapeControlChars(key)
+	}
+
+	return key
+}
+
+// escapeControlChars returns s with newlines, carriage returns and tabs escaped as \n, \r and \t and the other
+// control characters, e.g. the ESC of ANSI sequences, as \x1b or \u0085, so that s can't break or forge lines. Other
+// characters, including backslashes, are kept as is.
+func escapeControlChars(s string) string {
+	i := strings.IndexFunc(s, unicode.IsControl)
+	if i < 0 {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.WriteString(s[:i])
+	for _, r := range s[i:] {
+		switch {
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r < 0x80 && unicode.IsControl(r):
+			sb.WriteString(fmt.Sprintf(`\x%02x`, r))
+		case unicode.IsControl(r):
+			sb.WriteString(fmt.Sprintf(`\u%04x`, r))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+
+	return sb.String()
+}
+
+// truncateMessage returns the first max runes of msg followed by the number of bytes cut, msg as is if it's not longer
+// or max is zero or less.
+func truncateMessage(msg string, max int) string {
+	// A message of at most max bytes has at most max runes
+	if max <= 0 || len(msg) <= max {
+		return msg
+	}
+
+	runes := 0
+	for i := range msg {
+		if runes == max {
+			return msg[:i] + "...[truncated " + strconv.Itoa(len(msg)-i) + " bytes]"
+		}
+		runes++
+	}
+
+	return msg
+}
+
+// combinedCaller returns the file and line of the caller of entry as "<file>:<line>", false if the entry doesn't carry
+// the caller.
+func (f *formatter) combinedCaller(entry *logrus.Entry) (string, bool) {
+	file, ok := entry.Data["file"].(string)
+	if !ok {
+		return "", false
+	}
+
+	caller := f.callerValue("file", file).(string)
+	if line, ok := entry.Data["line"].(int); ok {
+		caller += ":" + strconv.Itoa(line)
+	}
+
+	return caller, true
+}
+
+// callerValue returns value of the caller field key shortened to the base name of the file or the package qualified
+// function name in CallerShort format.
+func (f *formatter) callerValue(key string, value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok || f.caller != CallerShort {
+		return value
+	}
+
+	switch key {
+	case "file":
+		return path.Base(s)
+	case "function":
+		return s[strings.LastIndex(s, "/")+1:]
+	default:
+		return value
+	}
+}
+
+// isRedacted reports whether the values of the fields named key are masked.
+func (f *formatter) isRedacted(key string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return len(f.redactedKeys) > 0 && f.redactedKeys[strings.ToLower(key)]
+}
+
+// fieldValue returns value of the field key, masked if key is redacted.
+func (f *formatter) fieldValue(key string, value interface{}) interface{} {
+	if len(f.redactedKeys) > 0 && f.redactedKeys[strings.ToLower(key)] {
+		return redactedValue
+	}
+
+	return value
+}
+
+// appVersion returns the configured version or the build time default.
+func (f *formatter) appVersion() string {
+	if f.version == "" {
+		return appVersion
+	}
+
+	return f.version
+}
+
+// jsonField is a key value pair of a JSON log line, kept in a slice to preserve the order of the keys.
+type jsonField struct {
+	key   string
+	value interface{}
+}
+
+// writeJSONField appends "key":value to the JSON object being built in sb. Errors are rendered with their message and
+// values which can't be marshaled are rendered as in fmt.Sprint.
+func writeJSONField(sb *bytes.Buffer, key string, value interface{}) error {
+	encodedKey, err := json.Marshal(key)
+	if err != nil {
+		return err
+	}
+	if err, ok := value.(error); ok {
+		value = err.Error()
+	}
+	encodedValue, err := json.Marshal(value)
+	if err != nil {
+		encodedValue, err = json.Marshal(fmt.Sprint(value))
+		if err != nil {
+			return err
+		}
+	}
+
+	if sb.Len() > 1 {
+		sb.WriteString(",")
+	}
+	sb.Write(encodedKey)
+	sb.WriteString(":")
+	sb.Write(encodedValue)
+
+	return nil
+}
+
+// customKeys returns the sorted keys of the entry's custom fields, i.e. all fields except the caller information.
+func customKeys(entry *logrus.Entry) []string {
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		if !isCallerKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// isCallerKey reports whether key is one of the fields holding caller information.
+func isCallerKey(key string) bool {
+	for _, callerKey := range callerKeys {
+		if key == callerKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+// textValue renders value of a custom field for the text layout, quoting errors and values containing spaces or
+// special characters.
+func textValue(value interface{}) string {
+	if err, ok := value.(error); ok {
+		return strconv.Quote(err.Error())
+	}
+
+	s := fmt.Sprint(value)
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-._/@^+:*", r) {
+			return strconv.Quote(s)
+		}
+	}
+
+	return s
+}
+
+// newLine returns the configured line separator, the one of the platform by default.
+func (f *formatter) newLine() string {
+	if f.lineSeparatorSet {
+		return f.lineSeparator
+	}
+
+	if runtime.GOOS == "windows" {
+		return "\r\n"
+	}
+
+	return "\n"
+}
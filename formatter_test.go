@@ -0,0 +1,541 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatterJSON(t *testing.T) {
+
+	mockEntry := logrus.Entry{
+		Message: `Test "quoted" message` + "\n",
+		Time:    data.Time,
+		Level:   logrus.WarnLevel,
+		Data:    logrus.Fields{"file": "main.go", "line": 33, "function": "main.main"},
+	}
+
+	f := formatter{prefix: "[test] ", format: FormatJSON}
+	actual, err := f.Format(&mockEntry)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	err = json.Unmarshal(actual, &decoded)
+	require.NoError(t, err)
+
+	require.Equal(t, "WARNING", decoded["level"])
+	require.Equal(t, data.Time.Format("2006-01-02T15:04:05.000Z07:00"), decoded["time"])
+	require.Equal(t, appVersion, decoded["version"])
+	require.Equal(t, "[test] ", decoded["prefix"])
+	require.Equal(t, mockEntry.Message, decoded["message"])
+	require.Equal(t, "main.go", decoded["file"])
+	require.Equal(t, float64(33), decoded["line"])
+	require.Equal(t, "main.main", decoded["function"])
+}
+
+func TestSetFormatJSON(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_json_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+
+	path := filepath.Join(dir, "app.log")
+	l := New(WithFilePath(path), WithConsole(false))
+	l.SetFormat(FormatJSON)
+
+	message := randStringBytes(30)
+	l.Errorf("%s", message)
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	err = json.Unmarshal(content, &decoded)
+	require.NoError(t, err)
+
+	require.Equal(t, "ERROR", decoded["level"])
+	require.Equal(t, message, decoded["message"])
+	require.True(t, strings.HasSuffix(decoded["file"].(string), "formatter_test.go"))
+	require.NotZero(t, decoded["line"])
+	require.Contains(t, decoded["function"], "TestSetFormatJSON")
+	require.NotContains(t, decoded, "prefix")
+}
+
+func TestFormatterTime(t *testing.T) {
+
+	mockEntry := logrus.Entry{
+		Message: data.Message,
+		Time:    time.Date(2021, 1, 26, 14, 37, 17, 123000000, time.FixedZone("TRT", 3*60*60)),
+		Level:   logrus.InfoLevel,
+		Data:    logrus.Fields{},
+	}
+
+	l := New(WithConsole(false))
+
+	// Default is RFC3339 with milliseconds in the entry's location
+	actual, err := l.formatter.Format(&mockEntry)
+	require.NoError(t, err)
+	require.Contains(t, string(actual), " 2021-01-26T14:37:17.123+03:00 ")
+
+	l.SetTimePrecision(PrecisionSecond)
+	actual, err = l.formatter.Format(&mockEntry)
+	require.NoError(t, err)
+	require.Contains(t, string(actual), " 2021-01-26T14:37:17+03:00 ")
+
+	// Fractions are zero padded
+	l.SetTimePrecision(PrecisionMicrosecond)
+	actual, err = l.formatter.Format(&mockEntry)
+	require.NoError(t, err)
+	require.Contains(t, string(actual), " 2021-01-26T14:37:17.123000+03:00 ")
+
+	l.SetTimePrecision(PrecisionNanosecond)
+	actual, err = l.formatter.Format(&mockEntry)
+	require.NoError(t, err)
+	require.Contains(t, string(actual), " 2021-01-26T14:37:17.123000000+03:00 ")
+
+	// Layout takes precedence over precision
+	l.SetTimeFormat("2006-01-02T15:04:05.00Z07:00")
+	err = l.SetTimeZone(time.UTC)
+	require.NoError(t, err)
+
+	actual, err = l.formatter.Format(&mockEntry)
+	require.NoError(t, err)
+	require.Contains(t, string(actual), " 2021-01-26T11:37:17.12Z ")
+
+	// Nil location is rejected and empty layout falls back to the default
+	err = l.SetTimeZone(nil)
+	require.Error(t, err)
+	l.SetTimeFormat("")
+
+	actual, err = l.formatter.Format(&mockEntry)
+	require.NoError(t, err)
+	require.Contains(t, string(actual), " 2021-01-26T11:37:17.123000000Z ")
+}
+
+func TestSetReportTimestamp(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetVersion("2.0.0")
+
+	l.SetReportTimestamp(false)
+	l.Infof("%s", "message")
+	require.True(t, strings.HasPrefix(buf.String(), "INFO 2.0.0 message file:"), buf.String())
+
+	buf.Reset()
+	require.NoError(t, l.SetFieldOrder([]string{FieldTime, FieldLevel, FieldMessage}))
+	l.Infof("%s", "message")
+	require.Equal(t, "INFO message\n", buf.String())
+	require.NoError(t, l.SetFieldOrder(nil))
+
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.Infof("%s", "message")
+	require.True(t, strings.HasPrefix(buf.String(), `{"level":"INFO","version":"2.0.0","message":"message"`), buf.String())
+
+	buf.Reset()
+	l.SetReportTimestamp(true)
+	l.Infof("%s", "message")
+	require.Contains(t, buf.String(), `"time":"`)
+}
+
+func TestSetReportVersion(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false), WithLevel(logrus.DebugLevel))
+	l.SetOutput(&buf)
+	l.SetVersion("2.0.0")
+	l.SetReportTimestamp(false)
+
+	l.SetReportVersion(false)
+	l.Infof("%s", "message")
+	require.True(t, strings.HasPrefix(buf.String(), "INFO message file:"), buf.String())
+
+	// Only at some levels
+	buf.Reset()
+	l.SetReportVersion(true, logrus.InfoLevel, logrus.WarnLevel)
+	l.Debugf("%s", "message")
+	l.Infof("%s", "message")
+	require.True(t, strings.HasPrefix(buf.String(), "DEBUG message file:"), buf.String())
+	require.Contains(t, buf.String(), "\nINFO 2.0.0 message file:")
+
+	buf.Reset()
+	require.NoError(t, l.SetFieldOrder([]string{FieldLevel, FieldVersion, FieldMessage}))
+	l.Debugf("%s", "message")
+	require.Equal(t, "DEBUG message\n", buf.String())
+	require.NoError(t, l.SetFieldOrder(nil))
+
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.Debugf("%s", "message")
+	l.Infof("%s", "message")
+	lines := strings.Split(buf.String(), "\n")
+	require.NotContains(t, lines[0], `"version"`)
+	require.Contains(t, lines[1], `"version":"2.0.0"`)
+
+	buf.Reset()
+	l.SetReportVersion(true)
+	l.Debugf("%s", "message")
+	require.Contains(t, buf.String(), `"version":"2.0.0"`)
+}
+
+func TestSetPrefix(t *testing.T) {
+
+	var buf syncBuffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetVersion("8b662d6")
+	l.SetPrefix("api: ")
+	l.SetLevelL(logrus.DebugLevel)
+	l.SetPrefix("worker: ")
+
+	// Prefix and the other settings stick together
+	l.Debugf("%s", "job started")
+	require.Equal(t, logrus.DebugLevel, l.GetLevel())
+	require.Contains(t, buf.String(), " 8b662d6 worker: job started ")
+
+	// Prefix can be changed while logging
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Infof("%s", "job running")
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		l.SetPrefix("worker " + strconv.Itoa(i) + ": ")
+	}
+	wg.Wait()
+	require.Equal(t, "worker 99: ", l.formatter.prefix)
+}
+
+func TestSetVersion(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetVersion("8b662d6")
+
+	message := randStringBytes(30)
+	l.Infof("%s", message)
+
+	split := strings.Split(buf.String(), " ")
+	require.Equal(t, "8b662d6", split[2])
+	require.Equal(t, message, split[3])
+
+	// Empty version restores the default
+	buf.Reset()
+	l.SetVersion("")
+	l.Infof("%s", message)
+
+	split = strings.Split(buf.String(), " ")
+	require.Equal(t, appVersion, split[2])
+}
+
+func TestSetLevelNames(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetLevelL(logrus.TraceLevel)
+
+	// Default tokens are stable
+	for _, level := range []logrus.Level{logrus.ErrorLevel, logrus.WarnLevel, logrus.InfoLevel, logrus.DebugLevel,
+		logrus.TraceLevel} {
+		buf.Reset()
+		l.log.Logf(level, "%s", "message")
+		require.True(t, strings.HasPrefix(buf.String(), convertLevel(level)+" "), buf.String())
+	}
+
+	l.SetLevelNames(map[logrus.Level]string{logrus.WarnLevel: "WARN"})
+	buf.Reset()
+	l.Warnf("%s", "message")
+	l.Errorf("%s", "message")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.True(t, strings.HasPrefix(lines[0], "WARN "))
+	require.True(t, strings.HasPrefix(lines[1], "ERROR "))
+
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.Warnf("%s", "message")
+	require.Contains(t, buf.String(), `"level":"WARN"`)
+
+	// Nil restores the defaults
+	buf.Reset()
+	l.SetLevelNames(nil)
+	l.Warnf("%s", "message")
+	require.Contains(t, buf.String(), `"level":"WARNING"`)
+}
+
+func TestSetFieldOrder(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetTimeFormat("2006-01-02")
+	l.SetVersion("2.0.0")
+	l.SetPrefix("api: ")
+
+	require.NoError(t, l.SetFieldOrder([]string{FieldTime, FieldLevel, FieldPrefix, FieldMessage}))
+	l.WithFields(map[string]interface{}{"user": "alice"}).Info("login")
+	require.Equal(t, time.Now().Format("2006-01-02")+" INFO api: login user=alice\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, l.SetFieldOrder([]string{FieldMessage, FieldCaller, FieldVersion}))
+	l.Infof("%s", "login")
+	require.True(t, strings.HasPrefix(buf.String(), "login file:"), buf.String())
+	require.Contains(t, buf.String(), "formatter_test.go:")
+	require.True(t, strings.HasSuffix(buf.String(), ".TestSetFieldOrder 2.0.0\n"), buf.String())
+
+	// Empty segments are skipped
+	buf.Reset()
+	l.SetPrefix("")
+	l.SetCallerFormat(CallerNone)
+	require.NoError(t, l.SetFieldOrder([]string{FieldLevel, FieldPrefix, FieldCaller, FieldMessage}))
+	l.Infof("%s", "login")
+	require.Equal(t, "INFO login\n", buf.String())
+
+	require.EqualError(t, l.SetFieldOrder([]string{FieldLevel, "host", FieldMessage}), `unknown field "host"`)
+	require.EqualError(t, l.SetFieldOrder([]string{FieldMessage, FieldMessage}), `field "message" is repeated`)
+	require.EqualError(t, l.SetFieldOrder([]string{FieldLevel, FieldTime}), "field order must include the message")
+	require.EqualError(t, l.SetFieldOrder([]string{}), "field order must include the message")
+
+	// Nil restores the default layout
+	buf.Reset()
+	require.NoError(t, l.SetFieldOrder(nil))
+	l.Infof("%s", "login")
+	require.True(t, strings.HasPrefix(buf.String(), "INFO "+time.Now().Format("2006-01-02")+" 2.0.0 login "))
+}
+
+func TestSetJSONFieldNames(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false), WithFormat(FormatJSON))
+	l.SetOutput(&buf)
+
+	require.NoError(t, l.SetJSONFieldNames(map[string]string{
+		"time":  "@timestamp",
+		"level": "log.level",
+		"file":  "log.origin.file.name",
+	}))
+	l.WithFields(map[string]interface{}{"@timestamp": "spoofed"}).Infof("%s", "renamed")
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.NotContains(t, decoded, "time")
+	require.NotContains(t, decoded, "level")
+	require.NotContains(t, decoded, "file")
+	require.Contains(t, decoded, "@timestamp")
+	require.Equal(t, "INFO", decoded["log.level"])
+	require.Equal(t, "renamed", decoded["message"])
+	require.Contains(t, decoded["log.origin.file.name"], "formatter_test.go")
+	require.Equal(t, "spoofed", decoded["fields.@timestamp"])
+	require.True(t, strings.HasPrefix(buf.String(), `{"log.level":"INFO","@timestamp":`), buf.String())
+
+	require.EqualError(t, l.SetJSONFieldNames(map[string]string{"time": "ts", "level": "ts"}),
+		`JSON fields "level" and "time" can't both be named "ts"`)
+	require.EqualError(t, l.SetJSONFieldNames(map[string]string{"time": "message"}),
+		`JSON fields "message" and "time" can't both be named "message"`)
+	require.EqualError(t, l.SetJSONFieldNames(map[string]string{"host": "host.name"}), `unknown JSON field "host"`)
+	require.EqualError(t, l.SetJSONFieldNames(map[string]string{"time": ""}),
+		`JSON field "time" can't be renamed to an empty name`)
+
+	// Swapping names is allowed
+	require.NoError(t, l.SetJSONFieldNames(map[string]string{"time": "level", "level": "time"}))
+
+	// Nil restores the default names
+	buf.Reset()
+	require.NoError(t, l.SetJSONFieldNames(nil))
+	l.Infof("%s", "default")
+	require.True(t, strings.HasPrefix(buf.String(), `{"level":"INFO","time":`), buf.String())
+}
+
+func TestSetLineSeparator(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.Infof("%s", "default")
+	require.True(t, strings.HasSuffix(buf.String(), (&formatter{}).newLine()))
+
+	buf.Reset()
+	l.SetLineSeparator("\x00")
+	l.Infof("%s", "first")
+	l.SetFormat(FormatJSON)
+	l.Infof("%s", "second")
+	records := strings.Split(buf.String(), "\x00")
+	require.Len(t, records, 3)
+	require.Contains(t, records[0], "first")
+	require.NotContains(t, records[0], "\n")
+	require.Contains(t, records[1], `"message":"second"`)
+	require.Empty(t, records[2])
+
+	// Empty separator is allowed with a single warning
+	buf.Reset()
+	l.SetLineSeparator("")
+	l.SetLineSeparator("")
+	l.Infof("%s", "first")
+	l.Infof("%s", "second")
+	require.Equal(t, 1, strings.Count(buf.String(), "Empty line separator"))
+	require.Contains(t, buf.String(), "concatenated\"}\x00{")
+	require.Contains(t, buf.String(), `"function":".TestSetLineSeparator"}{`)
+	require.True(t, strings.HasSuffix(buf.String(), "}"))
+}
+
+// upperFormatter wraps a formatter and upper cases its lines.
+type upperFormatter struct {
+	next logrus.Formatter
+}
+
+func (f upperFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	line, err := f.next.Format(entry)
+	return bytes.ToUpper(line), err
+}
+
+func TestSetFormatter(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetFormatter(&logrus.JSONFormatter{})
+
+	l.WithFields(map[string]interface{}{"request_id": 42}).Errorf("%s", "request failed")
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, "request failed", decoded["msg"])
+	require.Equal(t, "error", decoded["level"])
+	require.Equal(t, float64(42), decoded["request_id"])
+	require.Contains(t, decoded["file"], "formatter_test.go")
+	require.NotZero(t, decoded["line"])
+	require.Equal(t, ".TestSetFormatter", decoded["function"])
+
+	// Custom formatters can delegate to the built-in one
+	buf.Reset()
+	l.SetPrefix("api: ")
+	l.SetFormatter(upperFormatter{next: l.DefaultFormatter()})
+	l.Infof("%s", "request served")
+	require.True(t, strings.HasPrefix(buf.String(), "INFO "))
+	require.Contains(t, buf.String(), " API: REQUEST SERVED ")
+
+	// Nil restores the built-in layout
+	buf.Reset()
+	l.SetFormatter(nil)
+	l.Infof("%s", "request served")
+	require.Contains(t, buf.String(), " api: request served ")
+}
+
+func TestRedaction(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetRedactedKeys("password", "Token")
+	l.SetRedactPattern(regexp.MustCompile(`\b\d{4}[ -]?\d{4}[ -]?\d{4}[ -]?\d{4}\b`))
+
+	fields := map[string]interface{}{"Password": "hunter2", "token": "s3cr3t", "user": "john"}
+	l.WithFields(fields).Infof("charged card %s", "4111 1111 1111 1111")
+
+	line := buf.String()
+	require.NotContains(t, line, "hunter2")
+	require.NotContains(t, line, "s3cr3t")
+	require.NotContains(t, line, "4111")
+	require.Contains(t, line, "charged card *** ")
+	require.Contains(t, line, " Password=*** token=*** user=john")
+
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.WithFields(fields).Infof("charged card %s", "4111-1111-1111-1111")
+
+	var decoded map[string]interface{}
+	err := json.Unmarshal(buf.Bytes(), &decoded)
+	require.NoError(t, err)
+	require.NotContains(t, buf.String(), "hunter2")
+	require.Equal(t, "***", decoded["Password"])
+	require.Equal(t, "***", decoded["token"])
+	require.Equal(t, "john", decoded["user"])
+	require.Equal(t, "charged card ***", decoded["message"])
+}
+
+func TestSetEscapeControlChars(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	forged := "login failed\nINFO 2024-01-23T10:00:00.000+00:00 1.0.0 login succeeded"
+	l.Infof("%s", forged)
+	require.Len(t, strings.Split(strings.TrimSpace(buf.String()), "\n"), 2)
+
+	buf.Reset()
+	l.SetEscapeControlChars(true)
+	entry := l.WithFields(map[string]interface{}{"user\nid": "a\nb"})
+	entry.Infof("%s\r\tcolor \x1b[31mred\x1b[0m \u0085 C:\\temp", forged)
+	line := buf.String()
+	require.Equal(t, 1, strings.Count(line, "\n"), line)
+	require.Contains(t, line, ` login failed\nINFO 2024-01-23T10:00:00.000+00:00 1.0.0 login succeeded`+
+		`\r\tcolor \x1b[31mred\x1b[0m \u0085 C:\temp `)
+	require.True(t, strings.HasSuffix(line, ` user\nid="a\nb"`+"\n"), line)
+
+	// JSON escapes regardless
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.Infof("%s", "a\nb")
+	require.Contains(t, buf.String(), `"message":"a\nb"`)
+
+	buf.Reset()
+	l.SetFormat(FormatText)
+	l.SetEscapeControlChars(false)
+	l.Infof("%s", "a\nb")
+	require.Contains(t, buf.String(), " a\nb ")
+}
+
+func TestSetMaxMessageLength(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetMaxMessageLength(5)
+
+	// The boundary falls right after a multibyte rune, the next one is cut whole
+	l.WithFields(map[string]interface{}{"user": "jöhn"}).Infof("%s", "abcdéfgh")
+	require.Contains(t, buf.String(), " abcdé...[truncated 3 bytes] ")
+	require.Contains(t, buf.String(), " user=jöhn")
+
+	buf.Reset()
+	l.Infof("%s", "日本語のログ")
+	require.Contains(t, buf.String(), " 日本語のロ...[truncated 3 bytes] ")
+
+	buf.Reset()
+	l.Infof("%s", "short")
+	require.Contains(t, buf.String(), " short ")
+	require.NotContains(t, buf.String(), "truncated")
+
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.Infof("%s", "abcdéfgh")
+	require.Contains(t, buf.String(), `"message":"abcdé...[truncated 3 bytes]"`)
+
+	buf.Reset()
+	l.SetMaxMessageLength(0)
+	l.Infof("%s", "abcdéfgh")
+	require.Contains(t, buf.String(), `"message":"abcdéfgh"`)
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// FunctionNameStyle is how the name of the caller's function is reported in the function field.
+type FunctionNameStyle int
+
+const (
+	// FunctionFull reports the function with its import path and receiver as resolved by the runtime, e.g.
+	// github.com/acme/app/server.(*Server).Handle. It's the default.
+	FunctionFull FunctionNameStyle = iota
+	// FunctionShort reports the function with its receiver type only, e.g. Server.Handle, or the bare name of plain
+	// functions, e.g. Open.
+	FunctionShort
+)
+
+// SetFunctionNameStyle sets how the function field names the caller's function, FunctionFull by default. Unlike
+// SetCallerFormat it changes the field itself, so hooks and custom formatters receive the short name too.
+func (l *Logger) SetFunctionNameStyle(style FunctionNameStyle) {
+	atomic.StoreInt32(&l.functionStyle, int32(style))
+}
+
+// shortFunctionName returns function without its package path and with the parentheses and pointer of its receiver
+// removed, e.g. Server.Handle for github.com/acme/app/server.(*Server).Handle. Method values keep their name without
+// the -fm suffix of their wrapper.
+func shortFunctionName(function string) string {
+	name := function[strings.LastIndex(function, "/")+1:]
+	if dot := strings.Index(name, "."); dot >= 0 {
+		name = name[dot+1:]
+	}
+	if strings.HasPrefix(name, "(") {
+		if end := strings.Index(name, ")"); end > 0 {
+			name = strings.TrimPrefix(name[1:end], "*") + name[end+1:]
+		}
+	}
+
+	return strings.TrimSuffix(name, "-fm")
+}
+
+// SetFunctionNameStyle sets how the function field of the default logger names the caller's function. See
+// Logger.SetFunctionNameStyle.
+func SetFunctionNameStyle(style FunctionNameStyle) {
+	std.SetFunctionNameStyle(style)
+}
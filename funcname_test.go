@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// funcNameServer logs from a method with a pointer receiver.
+type funcNameServer struct {
+	logger *Logger
+}
+
+func (s *funcNameServer) handle() {
+	s.logger.Infof("handled")
+}
+
+func TestSetFunctionNameStyle(t *testing.T) {
+
+	l := New(WithConsole(false))
+	logs, restore := l.CaptureForTest()
+	defer restore()
+
+	srv := &funcNameServer{logger: l}
+	handle := srv.handle
+
+	handle()
+	require.Equal(t, ".(*funcNameServer).handle", logs.Entries()[0].Fields["function"])
+
+	l.SetFunctionNameStyle(FunctionShort)
+	handle()
+	require.Equal(t, "funcNameServer.handle", logs.Entries()[1].Fields["function"])
+	func() {
+		l.Infof("closure")
+	}()
+	require.Equal(t, "TestSetFunctionNameStyle.func1", logs.Entries()[2].Fields["function"])
+	require.Equal(t, FunctionShort, l.Config().FunctionNameStyle)
+}
+
+func TestShortFunctionName(t *testing.T) {
+
+	for function, want := range map[string]string{
+		"github.com/acme/app/server.(*Server).Handle":       "Server.Handle",
+		"github.com/acme/app/server.(Server).Handle":        "Server.Handle",
+		"github.com/acme/app/server.(*Server).Handle-fm":    "Server.Handle",
+		"github.com/acme/app/server.(*Server).Handle.func1": "Server.Handle.func1",
+		"github.com/acme/app/db.Open":                       "Open",
+		"main.main":                                         "main",
+	} {
+		require.Equal(t, want, shortFunctionName(function), function)
+	}
+}
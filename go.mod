@@ -3,7 +3,13 @@ module github.com/binalyze/logger
 go 1.16
 
 require (
-	github.com/sirupsen/logrus v1.8.1 // indirect
-	github.com/stretchr/testify v1.7.0 // indirect
-	gopkg.in/natefinch/lumberjack.v2 v2.0.0 // indirect
+	github.com/getsentry/sentry-go v0.13.0
+	github.com/klauspost/compress v1.15.1
+	github.com/prometheus/client_golang v1.11.1
+	github.com/segmentio/kafka-go v0.4.31
+	github.com/sirupsen/logrus v1.8.1
+	github.com/stretchr/testify v1.7.0
+	golang.org/x/sys v0.0.0-20211007075335-d3039528d8ac
+	google.golang.org/grpc v1.45.0
+	gopkg.in/natefinch/lumberjack.v2 v2.0.0
 )
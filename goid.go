@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+)
+
+// goroutineIDKey is the field holding the goroutine ID added by SetIncludeGoroutineID
+const goroutineIDKey = "goid"
+
+// SetIncludeGoroutineID adds the goid field holding the ID of the goroutine making the logging call to every line, e.g.
+// to follow the lines of one goroutine while debugging concurrency issues. Go doesn't expose goroutine IDs, so it's
+// parsed from the header of the goroutine's stack trace on every call, which is best-effort and costs about a
+// microsecond per line. The field is omitted if the ID can't be parsed, IDs may be reused once a goroutine ends. A
+// custom field with the same key takes precedence. Disabled by default.
+func (l *Logger) SetIncludeGoroutineID(enabled bool) {
+	var include int32
+	if enabled {
+		include = 1
+	}
+	atomic.StoreInt32(&l.goroutineID, include)
+}
+
+// goroutineID returns the ID of the current goroutine parsed from the "goroutine 42 [running]:" header of its stack
+// trace, zero if it can't be parsed.
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i > 0 {
+		header = header[:i]
+	}
+
+	id, err := strconv.ParseUint(string(header), 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return id
+}
+
+// SetIncludeGoroutineID adds the goid field to every line of the default logger. See Logger.SetIncludeGoroutineID.
+func SetIncludeGoroutineID(enabled bool) {
+	std.SetIncludeGoroutineID(enabled)
+}
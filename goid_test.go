@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetIncludeGoroutineID(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false), WithFormat(FormatJSON))
+	l.SetOutput(&buf)
+
+	l.Infof("%s", "message")
+	require.NotContains(t, buf.String(), `"goid":`)
+
+	l.SetIncludeGoroutineID(true)
+	ids := make([]float64, 2)
+	for i := range ids {
+		buf.Reset()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			l.Infof("%s", "message")
+		}()
+		<-done
+
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		id, ok := line["goid"].(float64)
+		require.True(t, ok, "goid should be numeric: %s", buf.String())
+		require.NotZero(t, id)
+		ids[i] = id
+	}
+	require.NotEqual(t, ids[0], ids[1])
+
+	// Custom fields take precedence
+	buf.Reset()
+	l.WithFields(map[string]interface{}{"goid": "custom"}).Infof("message")
+	require.Contains(t, buf.String(), `"goid":"custom"`)
+
+	buf.Reset()
+	l.SetIncludeGoroutineID(false)
+	l.Infof("%s", "message")
+	require.NotContains(t, buf.String(), `"goid":`)
+}
@@ -0,0 +1,126 @@
+//go:build grpc
+// +build grpc
+
+package logger
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// defaultGRPCErrorCodes are the status codes logged at level Error by default, i.e. the server side failures.
+var defaultGRPCErrorCodes = []codes.Code{
+	codes.Unknown,
+	codes.DeadlineExceeded,
+	codes.Unimplemented,
+	codes.Internal,
+	codes.Unavailable,
+	codes.DataLoss,
+}
+
+// GRPCOption configures the interceptors returned by UnaryServerInterceptor and StreamServerInterceptor.
+type GRPCOption func(*grpcConfig)
+
+// WithGRPCErrorCodes sets the status codes logged at level Error, the other calls are logged at level Info. By default
+// Unknown, DeadlineExceeded, Unimplemented, Internal, Unavailable and DataLoss are logged at level Error.
+func WithGRPCErrorCodes(errorCodes ...codes.Code) GRPCOption {
+	return func(c *grpcConfig) {
+		c.errorCodes = make(map[codes.Code]bool, len(errorCodes))
+		for _, code := range errorCodes {
+			c.errorCodes[code] = true
+		}
+	}
+}
+
+// grpcConfig holds the configuration of the interceptors.
+type grpcConfig struct {
+	errorCodes map[codes.Code]bool
+}
+
+// newGRPCConfig creates the configuration of an interceptor with opts applied.
+func newGRPCConfig(opts []GRPCOption) *grpcConfig {
+	c := &grpcConfig{}
+	WithGRPCErrorCodes(defaultGRPCErrorCodes...)(c)
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// UnaryServerInterceptor returns an interceptor logging every unary call with its method, status code, duration in
+// milliseconds in the numeric grpc.duration_ms field and peer address once it's finished. Calls with an error status
+// code are logged at level Error, see WithGRPCErrorCodes. It's only available when built with the grpc build tag.
+func (l *Logger) UnaryServerInterceptor(opts ...GRPCOption) grpc.UnaryServerInterceptor {
+	c := newGRPCConfig(opts)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		l.logGRPCCall(ctx, c, "finished unary call", info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns an interceptor logging every streaming call with its method, status code, duration in
+// milliseconds in the numeric grpc.duration_ms field and peer address once it's finished. Calls with an error status
+// code are logged at level Error, see WithGRPCErrorCodes. It's only available when built with the grpc build tag.
+func (l *Logger) StreamServerInterceptor(opts ...GRPCOption) grpc.StreamServerInterceptor {
+	c := newGRPCConfig(opts)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		l.logGRPCCall(ss.Context(), c, "finished streaming call", info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+// logGRPCCall logs a finished call. The caller fields are omitted since they'd point at the interceptor.
+func (l *Logger) logGRPCCall(ctx context.Context, c *grpcConfig, msg, method string, start time.Time, err error) {
+	code := status.Code(err)
+	level := logrus.InfoLevel
+	if c.errorCodes[code] {
+		level = logrus.ErrorLevel
+	}
+	if !l.isLevelEnabled(level) {
+		return
+	}
+
+	fields := copyFields(l.contextFields(ctx), map[string]interface{}{
+		"grpc.method":      method,
+		"grpc.code":        code.String(),
+		"grpc.duration_ms": float64(time.Since(start)) / float64(time.Millisecond),
+	})
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		fields["peer"] = p.Addr.String()
+	}
+	if err != nil {
+		fields["error"] = err
+	}
+
+	if entry := l.newEntry(0, level, fields, false); entry != nil {
+		l.write(entry, level, msg)
+	}
+}
+
+// UnaryServerInterceptor returns an interceptor logging unary calls with the default logger. See
+// Logger.UnaryServerInterceptor.
+func UnaryServerInterceptor(opts ...GRPCOption) grpc.UnaryServerInterceptor {
+	return std.UnaryServerInterceptor(opts...)
+}
+
+// StreamServerInterceptor returns an interceptor logging streaming calls with the default logger. See
+// Logger.StreamServerInterceptor.
+func StreamServerInterceptor(opts ...GRPCOption) grpc.StreamServerInterceptor {
+	return std.StreamServerInterceptor(opts...)
+}
@@ -0,0 +1,114 @@
+//go:build grpc
+// +build grpc
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// fakeServerStream is a grpc.ServerStream carrying a context.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000},
+	})
+	info := &grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"}
+	interceptor := l.UnaryServerInterceptor()
+
+	resp, err := interceptor(ctx, "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+
+	line := buf.String()
+	require.True(t, strings.HasPrefix(line, "INFO "))
+	require.Contains(t, line, " finished unary call ")
+	require.Contains(t, line, " grpc.code=OK ")
+	require.Contains(t, line, " grpc.duration_ms=")
+	require.Contains(t, line, " grpc.method=/users.Users/Get ")
+	require.Contains(t, line, " peer=10.0.0.1:5000")
+	require.NotContains(t, line, "file:")
+
+	// Server errors are logged at level Error, client errors at level Info
+	buf.Reset()
+	_, err = interceptor(ctx, "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.Internal, "database unavailable")
+	})
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(buf.String(), "ERROR "))
+	require.Contains(t, buf.String(), " grpc.code=Internal ")
+	require.Contains(t, buf.String(), `error="rpc error: code = Internal desc = database unavailable"`)
+
+	buf.Reset()
+	_, err = interceptor(ctx, "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "no such user")
+	})
+	require.Error(t, err)
+	require.True(t, strings.HasPrefix(buf.String(), "INFO "))
+
+	buf.Reset()
+	interceptor = l.UnaryServerInterceptor(WithGRPCErrorCodes(codes.NotFound))
+	_, _ = interceptor(ctx, "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, status.Error(codes.NotFound, "no such user")
+	})
+	require.True(t, strings.HasPrefix(buf.String(), "ERROR "))
+
+	// The duration is numeric and context fields named like a field of the logger are renamed
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	ctx = ContextWithFields(ctx, map[string]interface{}{"message": "spoofed"})
+	_, _ = interceptor(ctx, "request", info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return "response", nil
+	})
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &fields))
+	require.IsType(t, float64(0), fields["grpc.duration_ms"])
+	require.Equal(t, "finished unary call", fields["message"])
+	require.Equal(t, "spoofed", fields["fields.message"])
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	stream := &fakeServerStream{ctx: context.Background()}
+	info := &grpc.StreamServerInfo{FullMethod: "/users.Users/List"}
+	err := l.StreamServerInterceptor()(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return status.Error(codes.Unavailable, "shutting down")
+	})
+	require.Error(t, err)
+
+	line := buf.String()
+	require.True(t, strings.HasPrefix(line, "ERROR "))
+	require.Contains(t, line, " finished streaming call ")
+	require.Contains(t, line, " grpc.method=/users.Users/List")
+	require.NotContains(t, line, "peer=")
+}
@@ -0,0 +1,42 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// AddHook adds hook which is fired for the levels it reports, e.g. to integrate any logrus compatible hook. The entries
+// passed to hook carry the logger's fields like file and line. It's safe to add hooks after Init while logging from
+// other goroutines, the entries being logged at the time may not fire the new hook. The errors returned by hook are
+// reported to the handler set by SetInternalErrorHandler and don't keep the other hooks from firing.
+func (l *Logger) AddHook(hook logrus.Hook) {
+	l.hooksMu.Lock()
+	defer l.hooksMu.Unlock()
+
+	l.log.AddHook(reportingHook{Hook: hook, logger: l})
+}
+
+// Hooks returns a copy of the hooks by level, including the ones added by the logger itself like the console output.
+// Modifying the copy doesn't affect the logger.
+func (l *Logger) Hooks() logrus.LevelHooks {
+	l.hooksMu.Lock()
+	defer l.hooksMu.Unlock()
+
+	hooks := make(logrus.LevelHooks, len(l.log.Hooks))
+	for level, levelHooks := range l.log.Hooks {
+		for _, hook := range levelHooks {
+			hooks[level] = append(hooks[level], unwrapHook(hook))
+		}
+	}
+
+	return hooks
+}
+
+// AddHook adds hook to the default logger. See Logger.AddHook.
+func AddHook(hook logrus.Hook) {
+	std.AddHook(hook)
+}
+
+// Hooks returns a copy of the hooks of the default logger by level. See Logger.Hooks.
+func Hooks() logrus.LevelHooks {
+	return std.Hooks()
+}
@@ -0,0 +1,74 @@
+package logger
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// messageHook records the messages it's fired for.
+type messageHook struct {
+	mu       sync.Mutex
+	levels   []logrus.Level
+	messages []string
+}
+
+func (h *messageHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *messageHook) Fire(entry *logrus.Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.messages = append(h.messages, entry.Message)
+	return nil
+}
+
+func TestAddHook(t *testing.T) {
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+
+	hook := &messageHook{levels: []logrus.Level{logrus.ErrorLevel}}
+	l.AddHook(hook)
+	l.Infof("%s", "info")
+	l.Errorf("%s", "error")
+	require.Equal(t, []string{"error"}, hook.messages)
+
+	// The copy doesn't affect the logger
+	hooks := l.Hooks()
+	require.Contains(t, hooks[logrus.ErrorLevel], hook)
+	require.NotContains(t, hooks[logrus.InfoLevel], hook)
+	hooks[logrus.ErrorLevel] = nil
+	require.Contains(t, l.Hooks()[logrus.ErrorLevel], hook)
+}
+
+func TestAddHookConcurrent(t *testing.T) {
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Infof("%d", j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			l.AddHook(&messageHook{levels: logrus.AllLevels})
+			_ = l.Hooks()
+		}()
+	}
+	wg.Wait()
+
+	// The console hook and the added ones
+	require.Len(t, l.Hooks()[logrus.InfoLevel], 5)
+}
@@ -0,0 +1,188 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	httpHookQueueSize    = 256
+	httpHookTimeout      = 5 * time.Second
+	httpHookRetries      = 3
+	httpHookRetryBackoff = 500 * time.Millisecond
+)
+
+// HTTPHookOption configures a hook added by AddHTTPHook.
+type HTTPHookOption func(*httpHook)
+
+// WithHTTPHeader sets a header sent with every request, e.g. an authorization token.
+func WithHTTPHeader(key, value string) HTTPHookOption {
+	return func(h *httpHook) {
+		h.headers.Set(key, value)
+	}
+}
+
+// WithHTTPTimeout sets the timeout of a single request, 5 seconds by default.
+func WithHTTPTimeout(timeout time.Duration) HTTPHookOption {
+	return func(h *httpHook) {
+		h.client.Timeout = timeout
+	}
+}
+
+// WithHTTPRetries sets how many times a request is attempted before the entry is dropped, 3 by default.
+func WithHTTPRetries(retries int) HTTPHookOption {
+	return func(h *httpHook) {
+		h.retries = retries
+	}
+}
+
+// httpHook posts entries as JSON to an endpoint. Entries are queued and sent by a single goroutine so that logging
+// never blocks on the network, entries are dropped if the queue is full or all attempts fail.
+type httpHook struct {
+	url     string
+	levels  []logrus.Level
+	logger  *Logger
+	client  *http.Client
+	headers http.Header
+	retries int
+	queue   chan []byte
+	dropped uint64
+}
+
+// AddHTTPHook posts log lines at or above minLevel as JSON to rawURL, e.g. an incident endpoint. Requests are sent
+// asynchronously with a short timeout and a bounded retry, entries which can't be delivered are dropped without
+// affecting the caller. It returns an error if rawURL is not a valid http(s) URL.
+func (l *Logger) AddHTTPHook(rawURL string, minLevel logrus.Level, opts ...HTTPHookOption) error {
+	hook, err := newHTTPHook(rawURL, minLevel, l, opts...)
+	if err != nil {
+		return err
+	}
+
+	l.AddHook(hook)
+	go hook.run()
+
+	return nil
+}
+
+// newHTTPHook creates a hook posting entries of l at or above minLevel to rawURL.
+func newHTTPHook(rawURL string, minLevel logrus.Level, l *Logger, opts ...HTTPHookOption) (*httpHook, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported url scheme %q", u.Scheme)
+	}
+
+	h := &httpHook{
+		url:     rawURL,
+		levels:  levelsFrom(minLevel),
+		logger:  l,
+		client:  &http.Client{Timeout: httpHookTimeout},
+		headers: http.Header{},
+		retries: httpHookRetries,
+		queue:   make(chan []byte, httpHookQueueSize),
+	}
+	h.headers.Set("Content-Type", "application/json")
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h, nil
+}
+
+// Levels returns the levels the hook fires for.
+func (h *httpHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire queues entry to be posted, dropping it if the queue is full. The entry is formatted with the current formatter
+// of the logger, which Init replaces.
+func (h *httpHook) Fire(entry *logrus.Entry) error {
+	payload, err := h.logger.formatter.formatAs(entry, FormatJSON)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case h.queue <- payload:
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+	}
+
+	return nil
+}
+
+// run posts the queued entries.
+func (h *httpHook) run() {
+	for payload := range h.queue {
+		if err := h.post(payload); err != nil {
+			atomic.AddUint64(&h.dropped, 1)
+		}
+	}
+}
+
+// post sends payload retrying on network errors and server errors.
+func (h *httpHook) post(payload []byte) error {
+	var err error
+	for attempt := 0; attempt < h.retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * httpHookRetryBackoff)
+		}
+
+		var retry bool
+		retry, err = h.send(payload)
+		if !retry {
+			return err
+		}
+	}
+
+	return err
+}
+
+// send posts payload once and reports whether a failure is worth retrying.
+func (h *httpHook) send(payload []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, h.url, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header = h.headers.Clone()
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return true, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return false, nil
+}
+
+// levelsFrom returns the levels at or above minLevel, i.e. minLevel and the more severe ones.
+func levelsFrom(minLevel logrus.Level) []logrus.Level {
+	var levels []logrus.Level
+	for _, level := range logrus.AllLevels {
+		if level <= minLevel {
+			levels = append(levels, level)
+		}
+	}
+
+	return levels
+}
+
+// AddHTTPHook posts log lines of the default logger at or above minLevel as JSON to rawURL. See Logger.AddHTTPHook.
+func AddHTTPHook(rawURL string, minLevel logrus.Level, opts ...HTTPHookOption) error {
+	return std.AddHTTPHook(rawURL, minLevel, opts...)
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddHTTPHook(t *testing.T) {
+
+	var (
+		mu       sync.Mutex
+		received []map[string]interface{}
+		tokens   []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &decoded))
+
+		mu.Lock()
+		received = append(received, decoded)
+		tokens = append(tokens, r.Header.Get("Authorization"))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	l := New(WithConsole(false))
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+
+	err := l.AddHTTPHook(server.URL, logrus.ErrorLevel, WithHTTPHeader("Authorization", "Bearer token"))
+	require.NoError(t, err)
+
+	messageInfo := randStringBytes(30)
+	messageError := randStringBytes(30)
+	l.Infof("%s", messageInfo)
+	l.Errorf("%s", messageError)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Give a possible info post a chance to arrive
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 1)
+	require.Equal(t, messageError, received[0]["message"])
+	require.Equal(t, "ERROR", received[0]["level"])
+	require.Equal(t, "Bearer token", tokens[0])
+}
+
+func TestHTTPHookDropped(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	l := New(WithConsole(false))
+	hook, err := newHTTPHook(server.URL, logrus.ErrorLevel, l, WithHTTPRetries(2), WithHTTPTimeout(time.Second))
+	require.NoError(t, err)
+	go hook.run()
+
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+	l.log.AddHook(hook)
+
+	l.Errorf("%s", randStringBytes(30))
+
+	require.Eventually(t, func() bool {
+		return atomic.LoadUint64(&hook.dropped) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = newHTTPHook("ftp://example.com", logrus.ErrorLevel, l)
+	require.Error(t, err)
+}
+
+func TestHTTPHookFormatterAfterInit(t *testing.T) {
+
+	received := make(chan map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var decoded map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&decoded))
+		received <- decoded
+	}))
+	defer server.Close()
+
+	l := New(WithConsole(false))
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+	require.NoError(t, l.AddHTTPHook(server.URL, logrus.ErrorLevel))
+
+	// Settings made after Init reach the hook
+	require.NoError(t, l.init())
+	l.SetRedactedKeys("password")
+	l.WithFields(map[string]interface{}{"password": "hunter2"}).Errorf("login failed")
+
+	select {
+	case decoded := <-received:
+		require.Equal(t, "***", decoded["password"])
+	case <-time.After(5 * time.Second):
+		t.Fatal("no entry posted")
+	}
+}
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SetInternalErrorHandler sets fn to be called with the errors logrus would otherwise print to stderr in its own
+// format: hooks failing to fire, formatters failing to render a line and writes to the output failing. The errors are
+// wrapped with what failed, e.g. "failed to fire hook: connection refused". Failed writes to the log file are
+// reported to the handler set by SetWriteErrorHandler too. fn is called while the line is being logged and must not
+// log through the logger. A nil fn restores the default, printing the error as a single line to stderr.
+func (l *Logger) SetInternalErrorHandler(fn func(error)) {
+	l.internalErrorHandler.Store(fn)
+}
+
+// internalError reports err to the internal error handler, or to stderr if there is none.
+func (l *Logger) internalError(err error) {
+	if fn, _ := l.internalErrorHandler.Load().(func(error)); fn != nil {
+		fn(err)
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "logger: %v\n", err)
+}
+
+// reportingHook reports the errors of the hook it wraps to the internal error handler instead of returning them to
+// logrus, which would also skip the hooks fired after it.
+type reportingHook struct {
+	logrus.Hook
+	logger *Logger
+}
+
+// Fire fires the wrapped hook, reporting its error.
+func (h reportingHook) Fire(entry *logrus.Entry) error {
+	if err := h.Hook.Fire(entry); err != nil {
+		h.logger.internalError(fmt.Errorf("failed to fire hook: %w", err))
+	}
+
+	return nil
+}
+
+// unwrapHook returns the hook wrapped by reportingHook, or hook itself.
+func unwrapHook(hook logrus.Hook) logrus.Hook {
+	if h, ok := hook.(reportingHook); ok {
+		return h.Hook
+	}
+
+	return hook
+}
+
+// reportingFormatter reports the errors of the formatter to the internal error handler, the line is dropped.
+type reportingFormatter struct {
+	formatter *formatter
+	logger    *Logger
+}
+
+// Format renders entry, reporting the error if it can't.
+func (f reportingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	line, err := f.formatter.Format(entry)
+	if err != nil {
+		f.logger.internalError(fmt.Errorf("failed to format entry: %w", err))
+		return nil, nil
+	}
+
+	return line, nil
+}
+
+// reportingWriter reports the errors of writes to the output to the internal error handler.
+type reportingWriter struct {
+	io.Writer
+	logger *Logger
+}
+
+// Write writes p, reporting the error if it fails.
+func (w reportingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	if err != nil {
+		w.logger.internalError(fmt.Errorf("failed to write to log: %w", err))
+	}
+
+	return n, nil
+}
+
+// SetInternalErrorHandler sets the handler of the internal errors of the default logger. See
+// Logger.SetInternalErrorHandler.
+func SetInternalErrorHandler(fn func(error)) {
+	std.SetInternalErrorHandler(fn)
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// failingHook fails to fire.
+type failingHook struct{}
+
+func (failingHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (failingHook) Fire(*logrus.Entry) error {
+	return errors.New("connection refused")
+}
+
+// failingFormatter fails to render entries.
+type failingFormatter struct{}
+
+func (failingFormatter) Format(*logrus.Entry) ([]byte, error) {
+	return nil, errors.New("unsupported value")
+}
+
+// failingWriter fails to write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestSetInternalErrorHandler(t *testing.T) {
+
+	l := New(WithConsole(false))
+	var out bytes.Buffer
+	l.SetOutput(&out)
+
+	var errs []string
+	l.SetInternalErrorHandler(func(err error) {
+		errs = append(errs, err.Error())
+	})
+
+	// The failing hook doesn't keep the hooks added after it from firing
+	hook := &messageHook{levels: logrus.AllLevels}
+	l.AddHook(failingHook{})
+	l.AddHook(hook)
+	l.Infof("hooked")
+	require.Equal(t, []string{"failed to fire hook: connection refused"}, errs)
+	require.Equal(t, []string{"hooked"}, hook.messages)
+	require.Contains(t, out.String(), "hooked")
+	require.Contains(t, l.Hooks()[logrus.InfoLevel], failingHook{})
+
+	errs = nil
+	l.SetFormatter(failingFormatter{})
+	l.Infof("unformatted")
+	require.Contains(t, errs, "failed to format entry: unsupported value")
+	require.NotContains(t, out.String(), "unformatted")
+
+	errs = nil
+	l.SetFormatter(nil)
+	l.SetOutput(failingWriter{})
+	l.Infof("unwritten")
+	require.Contains(t, errs, "failed to write to log: disk full")
+
+	// Writes through Writer return the error instead
+	errs = nil
+	_, err := l.Writer().Write([]byte("raw\n"))
+	require.EqualError(t, err, "disk full")
+	require.Empty(t, errs)
+}
@@ -0,0 +1,150 @@
+//go:build linux
+// +build linux
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// journalSocket is the socket of the systemd journal native protocol.
+const journalSocket = "/run/systemd/journal/socket"
+
+// journaldHook sends entries to the systemd journal as structured fields.
+type journaldHook struct {
+	logger     *Logger
+	conn       *net.UnixConn
+	identifier string
+}
+
+// newJournaldHook creates a hook sending entries to the journal listening on socket.
+func newJournaldHook(l *Logger, socket string) (*journaldHook, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to journald: %w", err)
+	}
+
+	return &journaldHook{
+		logger:     l,
+		conn:       conn,
+		identifier: getLogFileName(""),
+	}, nil
+}
+
+// Levels returns the levels the hook fires for.
+func (h *journaldHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire sends entry with its message, priority, caller and custom fields.
+func (h *journaldHook) Fire(entry *logrus.Entry) error {
+	var buf bytes.Buffer
+
+	f := h.logger.formatter
+	f.mu.RLock()
+	writeJournalField(&buf, "MESSAGE", f.prefix+f.message(entry))
+	writeJournalField(&buf, "PRIORITY", strconv.Itoa(journalPriority(entry.Level)))
+	writeJournalField(&buf, "SYSLOG_IDENTIFIER", h.identifier)
+	writeJournalField(&buf, "VERSION", f.appVersion())
+	if file, ok := entry.Data["file"].(string); ok {
+		writeJournalField(&buf, "CODE_FILE", file)
+	}
+	if line, ok := entry.Data["line"].(int); ok {
+		writeJournalField(&buf, "CODE_LINE", strconv.Itoa(line))
+	}
+	if function, ok := entry.Data["function"].(string); ok {
+		writeJournalField(&buf, "CODE_FUNC", function)
+	}
+	for _, key := range customKeys(entry) {
+		value := f.fieldValue(key, entry.Data[key])
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		writeJournalField(&buf, journalFieldName(key), fmt.Sprint(value))
+	}
+	f.mu.RUnlock()
+
+	_, err := h.conn.Write(buf.Bytes())
+	return err
+}
+
+// writeJournalField appends a field in the journal native protocol. Values containing a newline are written with their
+// length since they can't be delimited by one.
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		buf.WriteString("=")
+		buf.WriteString(value)
+		buf.WriteString("\n")
+		return
+	}
+
+	buf.WriteString("\n")
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteString("\n")
+}
+
+// journalFieldName converts key to a valid journal field name: upper case letters, digits and underscores, not
+// starting with an underscore which is reserved for trusted fields or a digit.
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+
+	name = strings.TrimLeft(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "FIELD_" + name
+	}
+
+	return name
+}
+
+// journalPriority maps level to a syslog priority the same way as the syslog hook.
+func journalPriority(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return 2
+	case logrus.ErrorLevel:
+		return 3
+	case logrus.WarnLevel:
+		return 4
+	case logrus.InfoLevel:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// AddJournaldHook sends log lines to the systemd journal as structured fields, in addition to the logger's output.
+// Levels are mapped to PRIORITY like in AddSyslogHook, the caller is sent as CODE_FILE, CODE_LINE and CODE_FUNC and
+// custom fields with upper case names, e.g. request_id as REQUEST_ID. It returns an error if journald is not running.
+func (l *Logger) AddJournaldHook() error {
+	hook, err := newJournaldHook(l, journalSocket)
+	if err != nil {
+		return err
+	}
+
+	l.AddHook(hook)
+
+	return nil
+}
+
+// AddJournaldHook sends log lines of the default logger to the systemd journal. See Logger.AddJournaldHook.
+func AddJournaldHook() error {
+	return std.AddJournaldHook()
+}
@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+package logger
+
+import (
+	"errors"
+)
+
+// AddJournaldHook is not supported on this platform and always returns an error.
+func (l *Logger) AddJournaldHook() error {
+	return errors.New("journald is not supported on this platform")
+}
+
+// AddJournaldHook is not supported on this platform and always returns an error.
+func AddJournaldHook() error {
+	return std.AddJournaldHook()
+}
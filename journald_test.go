@@ -0,0 +1,84 @@
+//go:build linux
+// +build linux
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournaldHook(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "journald")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "socket")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	l := New(WithConsole(false))
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+	l.SetRedactedKeys("token")
+
+	hook, err := newJournaldHook(l, socket)
+	require.NoError(t, err)
+	l.log.AddHook(hook)
+
+	read := func() string {
+		packet := make([]byte, 65536)
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, err := conn.Read(packet)
+		require.NoError(t, err)
+		return string(packet[:n])
+	}
+
+	l.WithFields(map[string]interface{}{"request-id": 42, "token": "secret", "_uid": 0}).Errorf("%s", "request failed")
+	packet := read()
+	require.Contains(t, packet, "MESSAGE=request failed\n")
+	require.Contains(t, packet, "PRIORITY=3\n")
+	require.Contains(t, packet, "CODE_FILE=")
+	require.Contains(t, packet, "journald_test.go\n")
+	require.Contains(t, packet, "CODE_LINE=")
+	require.Contains(t, packet, "CODE_FUNC=")
+	require.Contains(t, packet, "REQUEST_ID=42\n")
+	require.Contains(t, packet, "TOKEN=***\n")
+	require.Contains(t, packet, "\nUID=0\n")
+	require.Contains(t, buf.String(), "request failed")
+
+	// Multiline values are sent with their length
+	l.Warnf("%s", "first\nsecond")
+	packet = read()
+	require.Contains(t, packet, "PRIORITY=4\n")
+	length := make([]byte, 8)
+	binary.LittleEndian.PutUint64(length, uint64(len("first\nsecond")))
+	require.True(t, strings.HasPrefix(packet, "MESSAGE\n"+string(length)+"first\nsecond\n"), packet)
+}
+
+func TestAddJournaldHookError(t *testing.T) {
+
+	l := New(WithConsole(false))
+	_, err := newJournaldHook(l, filepath.Join(os.TempDir(), "missing", "socket"))
+	require.Error(t, err)
+}
+
+func TestJournalFieldName(t *testing.T) {
+
+	require.Equal(t, "REQUEST_ID", journalFieldName("request_id"))
+	require.Equal(t, "HTTP_STATUS", journalFieldName("http.status"))
+	require.Equal(t, "PRIVATE", journalFieldName("__private"))
+	require.Equal(t, "FIELD_1ST", journalFieldName("1st"))
+	require.Equal(t, "FIELD_", journalFieldName("_"))
+}
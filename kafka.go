@@ -0,0 +1,233 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	kafkaQueueSize     = 4096
+	kafkaBatchSize     = 512
+	kafkaBatchInterval = time.Second
+)
+
+// kafkaMessage is a formatted entry produced to the topic.
+type kafkaMessage struct {
+	key   []byte
+	value []byte
+}
+
+// kafkaProducer produces batches of messages to a topic.
+type kafkaProducer interface {
+	produce(messages []kafkaMessage) error
+}
+
+// newKafkaProducer creates a producer of topic on brokers, registered by the file built with the kafka tag so that the
+// client is compiled in only if needed.
+var newKafkaProducer func(brokers []string, topic string) (kafkaProducer, error)
+
+// KafkaOption configures a hook added by AddKafkaHook.
+type KafkaOption func(*kafkaHook)
+
+// WithKafkaKey keys the messages by the value of field, e.g. "hostname", so that the entries with the same value land
+// on the same partition. Entries without the field are produced without a key.
+func WithKafkaKey(field string) KafkaOption {
+	return func(h *kafkaHook) {
+		h.keyField = field
+	}
+}
+
+// WithKafkaMinLevel produces only the entries at or above level, all levels by default.
+func WithKafkaMinLevel(level logrus.Level) KafkaOption {
+	return func(h *kafkaHook) {
+		h.levels = levelsFrom(level)
+	}
+}
+
+// kafkaHook produces entries as JSON to a Kafka topic in batches. Entries are queued and produced by a single goroutine
+// when a batch is full, the batch interval elapses or on flush, so that logging never blocks on the brokers. Entries
+// are dropped if the queue is full or the batch can't be produced.
+type kafkaHook struct {
+	logger   *Logger
+	producer kafkaProducer
+	keyField string
+	levels   []logrus.Level
+
+	queue     chan kafkaMessage
+	flushes   chan chan struct{}
+	batchSize int
+	interval  time.Duration
+	dropped   uint64
+}
+
+// AddKafkaHook produces log lines as JSON objects to topic on the Kafka brokers, e.g. []string{"kafka:9092"}. Lines are
+// produced in the background in batches of up to 512 lines at least every second, they're dropped if the queue of
+// 4096 lines overflows or a batch can't be produced, as reported by KafkaDropped. Flush and Close produce the pending
+// lines. It's only available when built with the kafka build tag and returns an error otherwise or if the producer
+// can't be created.
+func (l *Logger) AddKafkaHook(brokers []string, topic string, opts ...KafkaOption) error {
+	if newKafkaProducer == nil {
+		return errors.New("kafka is not available, it requires building with the kafka tag")
+	}
+	if len(brokers) == 0 || topic == "" {
+		return errors.New("kafka brokers and topic are required")
+	}
+
+	producer, err := newKafkaProducer(brokers, topic)
+	if err != nil {
+		return err
+	}
+
+	l.addKafkaHook(newKafkaHook(l, producer, opts...))
+
+	return nil
+}
+
+// newKafkaHook creates a hook producing entries of l with producer.
+func newKafkaHook(l *Logger, producer kafkaProducer, opts ...KafkaOption) *kafkaHook {
+	h := &kafkaHook{
+		logger:    l,
+		producer:  producer,
+		levels:    logrus.AllLevels,
+		queue:     make(chan kafkaMessage, kafkaQueueSize),
+		flushes:   make(chan chan struct{}),
+		batchSize: kafkaBatchSize,
+		interval:  kafkaBatchInterval,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// addKafkaHook registers hook with l and starts producing.
+func (l *Logger) addKafkaHook(hook *kafkaHook) {
+	l.mu.Lock()
+	l.kafkaHooks = append(l.kafkaHooks, hook)
+	l.mu.Unlock()
+
+	l.AddHook(hook)
+	go hook.run()
+}
+
+// Levels returns the levels the hook fires for.
+func (h *kafkaHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire queues entry to be produced, dropping it if the queue is full. Fatal and panic entries are produced before it
+// returns since the process is about to end.
+func (h *kafkaHook) Fire(entry *logrus.Entry) error {
+	value, err := h.logger.formatter.formatAs(entry, FormatJSON)
+	if err != nil {
+		return err
+	}
+
+	m := kafkaMessage{value: value}
+	if key, ok := entry.Data[h.keyField]; ok && h.keyField != "" {
+		m.key = []byte(fmt.Sprint(key))
+	}
+
+	select {
+	case h.queue <- m:
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+	}
+
+	if entry.Level <= logrus.FatalLevel {
+		h.flush()
+	}
+
+	return nil
+}
+
+// flush waits until the messages queued so far are produced.
+func (h *kafkaHook) flush() {
+	done := make(chan struct{})
+	h.flushes <- done
+	<-done
+}
+
+// run batches the queued messages and produces the batches.
+func (h *kafkaHook) run() {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	batch := make([]kafkaMessage, 0, h.batchSize)
+	add := func(m kafkaMessage) {
+		batch = append(batch, m)
+		if len(batch) >= h.batchSize {
+			h.produce(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case m := <-h.queue:
+			add(m)
+		case <-ticker.C:
+			if len(batch) > 0 {
+				h.produce(batch)
+				batch = batch[:0]
+			}
+		case done := <-h.flushes:
+			for queued := true; queued; {
+				select {
+				case m := <-h.queue:
+					add(m)
+				default:
+					queued = false
+				}
+			}
+			if len(batch) > 0 {
+				h.produce(batch)
+				batch = batch[:0]
+			}
+			close(done)
+		}
+	}
+}
+
+// produce sends batch, counting its messages as dropped if it can't be produced.
+func (h *kafkaHook) produce(batch []kafkaMessage) {
+	if err := h.producer.produce(batch); err != nil {
+		atomic.AddUint64(&h.dropped, uint64(len(batch)))
+	}
+}
+
+// KafkaDropped returns the number of lines dropped by the Kafka hooks because their queue overflowed or the lines
+// couldn't be produced.
+func (l *Logger) KafkaDropped() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var dropped uint64
+	for _, hook := range l.kafkaHooks {
+		dropped += atomic.LoadUint64(&hook.dropped)
+	}
+
+	return dropped
+}
+
+// flushKafka produces the pending messages of the Kafka hooks, callers must hold l.mu.
+func (l *Logger) flushKafka() {
+	for _, hook := range l.kafkaHooks {
+		hook.flush()
+	}
+}
+
+// AddKafkaHook produces log lines of the default logger to a Kafka topic. See Logger.AddKafkaHook.
+func AddKafkaHook(brokers []string, topic string, opts ...KafkaOption) error {
+	return std.AddKafkaHook(brokers, topic, opts...)
+}
+
+// KafkaDropped returns the number of lines dropped by the Kafka hooks of the default logger. See Logger.KafkaDropped.
+func KafkaDropped() uint64 {
+	return std.KafkaDropped()
+}
@@ -0,0 +1,48 @@
+//go:build kafka
+// +build kafka
+
+package logger
+
+import (
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaWriteTimeout bounds how long a batch is attempted before it's dropped.
+const kafkaWriteTimeout = 10 * time.Second
+
+func init() {
+	newKafkaProducer = func(brokers []string, topic string) (kafkaProducer, error) {
+		return &kafkaWriter{
+			writer: &kafka.Writer{
+				Addr:  kafka.TCP(brokers...),
+				Topic: topic,
+				// Messages with the same key land on the same partition, the others are spread round robin
+				Balancer: &kafka.Hash{},
+				// The hook batches already, the writer shouldn't wait for more messages
+				BatchSize:    kafkaBatchSize,
+				BatchTimeout: time.Millisecond,
+			},
+		}, nil
+	}
+}
+
+// kafkaWriter produces messages with the kafka-go client.
+type kafkaWriter struct {
+	writer *kafka.Writer
+}
+
+// produce writes messages, waiting until they're acknowledged.
+func (w *kafkaWriter) produce(messages []kafkaMessage) error {
+	batch := make([]kafka.Message, len(messages))
+	for i, m := range messages {
+		batch[i] = kafka.Message{Key: m.key, Value: m.value}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaWriteTimeout)
+	defer cancel()
+
+	return w.writer.WriteMessages(ctx, batch...)
+}
@@ -0,0 +1,18 @@
+//go:build kafka
+// +build kafka
+
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddKafkaHook(t *testing.T) {
+
+	l := New(WithConsole(false))
+	require.Error(t, l.AddKafkaHook(nil, "logs"))
+	require.NoError(t, l.AddKafkaHook([]string{"localhost:9092"}, "logs", WithKafkaKey("hostname")))
+	require.Len(t, l.kafkaHooks, 1)
+}
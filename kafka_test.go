@@ -0,0 +1,95 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// mockProducer records the produced messages, failing if err is set.
+type mockProducer struct {
+	mu      sync.Mutex
+	batches [][]kafkaMessage
+	err     error
+}
+
+func (p *mockProducer) produce(messages []kafkaMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	p.batches = append(p.batches, append([]kafkaMessage(nil), messages...))
+
+	return nil
+}
+
+// messages returns the produced messages.
+func (p *mockProducer) messages() []kafkaMessage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var messages []kafkaMessage
+	for _, batch := range p.batches {
+		messages = append(messages, batch...)
+	}
+
+	return messages
+}
+
+func TestKafkaHook(t *testing.T) {
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+
+	producer := &mockProducer{}
+	l.addKafkaHook(newKafkaHook(l, producer, WithKafkaKey("hostname"), WithKafkaMinLevel(logrus.InfoLevel)))
+
+	l.WithFields(map[string]interface{}{"hostname": "web-1"}).Warn("disk almost full")
+	l.Infof("%s", "served")
+	l.Debugf("%s", "hidden")
+	require.NoError(t, l.Close())
+
+	messages := producer.messages()
+	require.Len(t, messages, 2)
+	require.Equal(t, "web-1", string(messages[0].key))
+	require.Nil(t, messages[1].key)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(messages[0].value, &decoded))
+	require.Equal(t, "WARNING", decoded["level"])
+	require.Equal(t, "disk almost full", decoded["message"])
+	require.Equal(t, "web-1", decoded["hostname"])
+	require.Zero(t, l.KafkaDropped())
+}
+
+func TestKafkaHookDropped(t *testing.T) {
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+
+	producer := &mockProducer{err: errors.New("broker unavailable")}
+	l.addKafkaHook(newKafkaHook(l, producer))
+
+	l.Infof("%s", "lost")
+	l.Infof("%s", "lost too")
+	require.NoError(t, l.Flush())
+	require.Equal(t, uint64(2), l.KafkaDropped())
+}
+
+func TestAddKafkaHookUnavailable(t *testing.T) {
+
+	if newKafkaProducer != nil {
+		t.Skip("built with the kafka tag")
+	}
+
+	l := New(WithConsole(false))
+	err := l.AddKafkaHook([]string{"localhost:9092"}, "logs")
+	require.EqualError(t, err, "kafka is not available, it requires building with the kafka tag")
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Tracew logs msg at level Trace with the fields paired up from keysAndValues.
+func (l *Logger) Tracew(msg string, keysAndValues ...interface{}) {
+	l.logw(logrus.TraceLevel, msg, keysAndValues...)
+}
+
+// Debugw logs msg at level Debug with the fields paired up from keysAndValues.
+func (l *Logger) Debugw(msg string, keysAndValues ...interface{}) {
+	l.logw(logrus.DebugLevel, msg, keysAndValues...)
+}
+
+// Infow logs msg at level Info with the fields paired up from keysAndValues, e.g. Infow("login", "user", id).
+func (l *Logger) Infow(msg string, keysAndValues ...interface{}) {
+	l.logw(logrus.InfoLevel, msg, keysAndValues...)
+}
+
+// Warnw logs msg at level Warn with the fields paired up from keysAndValues.
+func (l *Logger) Warnw(msg string, keysAndValues ...interface{}) {
+	l.logw(logrus.WarnLevel, msg, keysAndValues...)
+}
+
+// Errorw logs msg at level Error with the fields paired up from keysAndValues.
+func (l *Logger) Errorw(msg string, keysAndValues ...interface{}) {
+	l.logw(logrus.ErrorLevel, msg, keysAndValues...)
+}
+
+// Fatalw logs msg at level Fatal with the fields paired up from keysAndValues and exits.
+func (l *Logger) Fatalw(msg string, keysAndValues ...interface{}) {
+	l.logw(logrus.FatalLevel, msg, keysAndValues...)
+	l.exit()
+}
+
+// Panicw logs msg at level Panic with the fields paired up from keysAndValues and panics.
+func (l *Logger) Panicw(msg string, keysAndValues ...interface{}) {
+	l.logw(logrus.PanicLevel, msg, keysAndValues...)
+}
+
+// logw logs msg with the fields paired up from keysAndValues if level is enabled. Exported helpers must call it
+// directly so that the caller frame is always skipFrameCount frames away.
+func (l *Logger) logw(level logrus.Level, msg string, keysAndValues ...interface{}) {
+	if !l.isLevelEnabled(level) {
+		return
+	}
+
+	if entry := l.newEntry(0, level, keyValueFields(keysAndValues), true); entry != nil {
+		l.write(entry, level, msg)
+	}
+}
+
+// keyValueFields pairs up keysAndValues into fields, keys which aren't strings are converted as in fmt.Sprint. A key
+// without a value is reported in the "warning" field instead.
+func keyValueFields(keysAndValues []interface{}) logrus.Fields {
+	if len(keysAndValues) == 0 {
+		return nil
+	}
+
+	fields := make(logrus.Fields, len(keysAndValues)/2+1)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		fields[key] = keysAndValues[i+1]
+	}
+	if len(keysAndValues)%2 != 0 {
+		fields["warning"] = fmt.Sprintf("missing value for key %v", keysAndValues[len(keysAndValues)-1])
+	}
+
+	return fields
+}
+
+// Tracew logs msg at level Trace on the standard logger with the fields paired up from keysAndValues.
+func Tracew(msg string, keysAndValues ...interface{}) {
+	std.logw(logrus.TraceLevel, msg, keysAndValues...)
+}
+
+// Debugw logs msg at level Debug on the standard logger with the fields paired up from keysAndValues.
+func Debugw(msg string, keysAndValues ...interface{}) {
+	std.logw(logrus.DebugLevel, msg, keysAndValues...)
+}
+
+// Infow logs msg at level Info on the standard logger with the fields paired up from keysAndValues.
+func Infow(msg string, keysAndValues ...interface{}) {
+	std.logw(logrus.InfoLevel, msg, keysAndValues...)
+}
+
+// Warnw logs msg at level Warn on the standard logger with the fields paired up from keysAndValues.
+func Warnw(msg string, keysAndValues ...interface{}) {
+	std.logw(logrus.WarnLevel, msg, keysAndValues...)
+}
+
+// Errorw logs msg at level Error on the standard logger with the fields paired up from keysAndValues.
+func Errorw(msg string, keysAndValues ...interface{}) {
+	std.logw(logrus.ErrorLevel, msg, keysAndValues...)
+}
+
+// Fatalw logs msg at level Fatal on the standard logger with the fields paired up from keysAndValues and exits.
+func Fatalw(msg string, keysAndValues ...interface{}) {
+	std.logw(logrus.FatalLevel, msg, keysAndValues...)
+	std.exit()
+}
+
+// Panicw logs msg at level Panic on the standard logger with the fields paired up from keysAndValues and panics.
+func Panicw(msg string, keysAndValues ...interface{}) {
+	std.logw(logrus.PanicLevel, msg, keysAndValues...)
+}
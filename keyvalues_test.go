@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInfow(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.Infow("login", "user", "alice", "ip", "10.0.0.1", 42, true)
+	line := strings.TrimSpace(buf.String())
+	require.True(t, strings.HasPrefix(line, "INFO "))
+	require.Contains(t, line, " login file:")
+	require.Contains(t, line, "keyvalues_test.go:")
+	require.True(t, strings.HasSuffix(line, "func:.TestInfow 42=true ip=10.0.0.1 user=alice"))
+
+	// A key without a value is reported instead of panicking
+	buf.Reset()
+	l.Warnw("login", "user", "alice", "ip")
+	line = strings.TrimSpace(buf.String())
+	require.True(t, strings.HasPrefix(line, "WARNING "))
+	require.True(t, strings.HasSuffix(line, ` user=alice warning="missing value for key ip"`))
+
+	// Reserved keys are prefixed
+	buf.Reset()
+	l.Errorw("failed", "level", "high")
+	require.Contains(t, buf.String(), " fields.level=high")
+
+	buf.Reset()
+	l.Debugw("not logged", "user", "alice")
+	require.Empty(t, buf.String())
+}
+
+func TestFatalw(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	exited := false
+	l.log.ExitFunc = func(int) { exited = true }
+
+	l.Fatalw("shutting down", "reason", "signal")
+	require.True(t, exited)
+	require.Contains(t, buf.String(), "reason=signal")
+}
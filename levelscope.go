@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RunWithLevel sets level for the duration of fn and restores the previous level when fn returns or panics, e.g. to
+// debug a single operation without raising the verbosity for good:
+//
+//	l.RunWithLevel(logrus.DebugLevel, func() {
+//		err = sync(ctx)
+//	})
+//
+// The level is the logger's level, not a goroutine's: lines logged by other goroutines while fn runs are logged at
+// level too, and a level set concurrently, e.g. by another RunWithLevel or SetLevel, is overwritten on return. Nested
+// calls restore the levels in order.
+func (l *Logger) RunWithLevel(level logrus.Level, fn func()) {
+	previous := l.log.GetLevel()
+	levelSet := atomic.LoadInt32(&l.levelSet)
+	defer func() {
+		l.flushBurst()
+		l.log.SetLevel(previous)
+		atomic.StoreInt32(&l.levelSet, levelSet)
+	}()
+
+	l.SetLevelL(level)
+	fn()
+}
+
+// RunWithLevel sets the level of the default logger for the duration of fn. See Logger.RunWithLevel.
+func RunWithLevel(level logrus.Level, fn func()) {
+	std.RunWithLevel(level, fn)
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunWithLevel(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.RunWithLevel(logrus.DebugLevel, func() {
+		l.Debugf("inside")
+
+		l.RunWithLevel(logrus.TraceLevel, func() {
+			l.Tracef("nested")
+		})
+		require.Equal(t, logrus.DebugLevel, l.GetLevel())
+	})
+	l.Debugf("outside")
+
+	require.Equal(t, logrus.InfoLevel, l.GetLevel())
+	require.Contains(t, buf.String(), "inside")
+	require.Contains(t, buf.String(), "nested")
+	require.NotContains(t, buf.String(), "outside")
+
+	// The level is restored on panic
+	require.Panics(t, func() {
+		l.RunWithLevel(logrus.DebugLevel, func() {
+			panic("failed")
+		})
+	})
+	require.Equal(t, logrus.InfoLevel, l.GetLevel())
+	require.Zero(t, l.levelSet)
+}
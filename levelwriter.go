@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxParsedLineLength is the length at which a line without newline is logged anyway so that the buffer stays bounded.
+const maxParsedLineLength = 64 * 1024
+
+// levelParsingWriter logs the lines written to it at the level of their leading token.
+type levelParsingWriter struct {
+	logger *Logger
+
+	mu      sync.Mutex
+	pending []byte
+}
+
+// LevelParsingWriter returns a writer logging each line written to it at the level named by its leading token, e.g.
+// ERROR, [warn] or INFO:, falling back to Info if the line doesn't start with a level. The level names set by
+// SetLevelNames are recognized too. The token is removed from the message. Fatal and panic lines are logged at Fatal
+// without exiting or panicking. Lines may be split across writes, the last line is logged when it's terminated or on
+// Close. It's meant for piping the output of a child process:
+//
+//	cmd.Stderr = logger.LevelParsingWriter()
+func (l *Logger) LevelParsingWriter() io.WriteCloser {
+	return &levelParsingWriter{logger: l}
+}
+
+// Write logs the complete lines of p and keeps the rest until it's terminated.
+func (w *levelParsingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		w.logLine(string(w.pending[:i]))
+		w.pending = w.pending[i+1:]
+	}
+
+	if len(w.pending) >= maxParsedLineLength {
+		w.logLine(string(w.pending))
+		w.pending = nil
+	}
+
+	return len(p), nil
+}
+
+// Close logs the unterminated last line if there is one.
+func (w *levelParsingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.pending) > 0 {
+		w.logLine(string(w.pending))
+		w.pending = nil
+	}
+
+	return nil
+}
+
+// logLine logs line at the level of its leading token. The caller isn't reported since it would be the goroutine
+// copying the output.
+func (w *levelParsingWriter) logLine(line string) {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+
+	level, msg := w.logger.parseLeadingLevel(line)
+	if !w.logger.isLevelEnabled(level) {
+		return
+	}
+
+	if entry := w.logger.newEntry(0, level, nil, false); entry != nil {
+		w.logger.write(entry, level, msg)
+	}
+}
+
+// parseLeadingLevel returns the level named by the first token of line and line without it, or Info and line as is if
+// the token isn't a level.
+func (l *Logger) parseLeadingLevel(line string) (logrus.Level, string) {
+	trimmed := strings.TrimLeft(line, " \t")
+	end := strings.IndexAny(trimmed, " \t")
+	if end < 0 {
+		end = len(trimmed)
+	}
+
+	token := strings.Trim(trimmed[:end], "[]:")
+	level, ok := l.formatter.parseLevelName(token)
+	if !ok {
+		return logrus.InfoLevel, line
+	}
+	if level < logrus.FatalLevel {
+		level = logrus.FatalLevel
+	}
+
+	return level, strings.TrimLeft(trimmed[end:], " \t")
+}
+
+// parseLevelName returns the level named token in any case, either by its logrus name, e.g. warn, or the name rendered
+// in log lines, e.g. WARNING.
+func (f *formatter) parseLevelName(token string) (logrus.Level, bool) {
+	if token == "" {
+		return 0, false
+	}
+	if level, err := logrus.ParseLevel(token); err == nil {
+		return level, true
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, level := range logrus.AllLevels {
+		if strings.EqualFold(f.levelName(level), token) {
+			return level, true
+		}
+	}
+
+	return 0, false
+}
+
+// LevelParsingWriter returns a writer logging lines through the default logger at the level of their leading token.
+// See Logger.LevelParsingWriter.
+func LevelParsingWriter() io.WriteCloser {
+	return std.LevelParsingWriter()
+}
@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelParsingWriter(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false), WithLevel(logrus.DebugLevel))
+	l.SetOutput(&buf)
+	l.SetReportTimestamp(false)
+
+	w := l.LevelParsingWriter()
+	_, err := io.WriteString(w, "ERROR 2024-01-23T10:00:00Z disk full\n[warn] retrying\nDEBUG: conn")
+	require.NoError(t, err)
+	_, err = io.WriteString(w, "ected\r\nplain line\n\nFATAL crashed\n")
+	require.NoError(t, err)
+
+	// The last line is logged once it's terminated or on Close
+	_, err = io.WriteString(w, "WARNING unterminated")
+	require.NoError(t, err)
+	require.NotContains(t, buf.String(), "unterminated")
+	require.NoError(t, w.Close())
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 6)
+	require.Contains(t, string(lines[0]), "ERROR 1.0.0 2024-01-23T10:00:00Z disk full")
+	require.Contains(t, string(lines[1]), "WARNING 1.0.0 retrying")
+	require.Contains(t, string(lines[2]), "DEBUG 1.0.0 connected")
+	require.Contains(t, string(lines[3]), "INFO 1.0.0 plain line")
+	require.Contains(t, string(lines[4]), "FATAL 1.0.0 crashed")
+	require.Contains(t, string(lines[5]), "WARNING 1.0.0 unterminated")
+	require.NotContains(t, buf.String(), "file:")
+}
+
+func TestLevelParsingWriterLevelNames(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetLevelNames(map[logrus.Level]string{logrus.ErrorLevel: "ERR"})
+
+	_, err := io.WriteString(l.LevelParsingWriter(), "err: failed\n")
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "ERR ")
+	require.Contains(t, buf.String(), " failed")
+
+	// Levels below the logger's level are dropped
+	buf.Reset()
+	_, err = io.WriteString(l.LevelParsingWriter(), "debug hidden\n")
+	require.NoError(t, err)
+	require.Empty(t, buf.String())
+}
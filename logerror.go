@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogError logs a message at level Error with err attached as by WithError and returns err, so that an error can be
+// logged and returned in one statement:
+//
+//	return logger.LogError(err, "save failed for %s", id)
+//
+// A nil err logs the message without error fields and returns nil.
+func (l *Logger) LogError(err error, format string, args ...interface{}) error {
+	// The error fields are only built if the line is logged
+	if l.isLevelEnabled(logrus.ErrorLevel) {
+		l.logfFields(logrus.ErrorLevel, l.errorFields(nil, err), format, args...)
+	}
+	return err
+}
+
+// LogErrorf creates an error with fmt.Errorf, logs its message at level Error and returns it. The error wrapped with
+// %w, if any, is attached as by WithError:
+//
+//	return logger.LogErrorf("save failed for %s: %w", id, err)
+func (l *Logger) LogErrorf(format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	if l.isLevelEnabled(logrus.ErrorLevel) {
+		l.logfFields(logrus.ErrorLevel, l.errorFields(nil, errors.Unwrap(err)), "%s", err.Error())
+	}
+	return err
+}
+
+// LogError logs a message through the default logger with err attached and returns err. See Logger.LogError.
+func LogError(err error, format string, args ...interface{}) error {
+	if std.isLevelEnabled(logrus.ErrorLevel) {
+		std.logfFields(logrus.ErrorLevel, std.errorFields(nil, err), format, args...)
+	}
+	return err
+}
+
+// LogErrorf creates an error with fmt.Errorf, logs it through the default logger and returns it. See
+// Logger.LogErrorf.
+func LogErrorf(format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	if std.isLevelEnabled(logrus.ErrorLevel) {
+		std.logfFields(logrus.ErrorLevel, std.errorFields(nil, errors.Unwrap(err)), "%s", err.Error())
+	}
+	return err
+}
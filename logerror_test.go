@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogError(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	save := func(id string, err error) error {
+		return l.LogError(err, "save failed for %s", id)
+	}
+
+	errDisk := errors.New("disk full")
+	require.Same(t, errDisk, save("42", errDisk))
+	require.Contains(t, buf.String(), "ERROR ")
+	require.Contains(t, buf.String(), "save failed for 42")
+	require.Contains(t, buf.String(), `error="disk full"`)
+	require.Contains(t, buf.String(), "func:.TestLogError.func1")
+
+	// A nil error is logged without fields and returned
+	buf.Reset()
+	require.NoError(t, save("43", nil))
+	require.Contains(t, buf.String(), "save failed for 43")
+	require.NotContains(t, buf.String(), "error=")
+}
+
+func TestLogErrorf(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	errDisk := errors.New("disk full")
+	err := l.LogErrorf("save failed for %s: %w", "42", errDisk)
+	require.EqualError(t, err, "save failed for 42: disk full")
+	require.True(t, errors.Is(err, errDisk))
+	require.Contains(t, buf.String(), "ERROR ")
+	require.Contains(t, buf.String(), "save failed for 42: disk full")
+	require.Contains(t, buf.String(), `error="disk full"`)
+	require.Contains(t, buf.String(), "file:logerror_test.go")
+
+	buf.Reset()
+	err = l.LogErrorf("invalid id %d", 7)
+	require.EqualError(t, err, "invalid id 7")
+	require.Contains(t, buf.String(), "invalid id 7")
+	require.NotContains(t, buf.String(), "error=")
+}
+
+func TestLogErrorDefaultLogger(t *testing.T) {
+
+	defer ResetForTest()
+	var buf bytes.Buffer
+	SetOutput(&buf)
+
+	errDisk := errors.New("disk full")
+	require.Same(t, errDisk, LogError(errDisk, "save failed"))
+	require.Error(t, LogErrorf("save failed: %w", errDisk))
+
+	// The caller of the package helpers is reported, not the helpers
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		require.Contains(t, line, "file:logerror_test.go")
+		require.Contains(t, line, "func:.TestLogErrorDefaultLogger")
+	}
+}
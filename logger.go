@@ -1,14 +1,21 @@
 package logger
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
 	"runtime"
+	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -16,122 +23,1176 @@ import (
 )
 
 const (
-	skipFrameCount    = 4
+	skipFrameCount    = 5
 	splitAfterPkgName = "github.com/binalyze/logger"
 
+	// callerFieldCount is the number of caller fields, file, line and function
+	callerFieldCount = 3
+
 	envLogToConsole = "LOG_TO_CONSOLE"
 
+	// defaultAppName names the log file if the executable name is unknown
+	defaultAppName = "app"
+
 	maxSizeInMBs         = 10
 	maxBackups           = 3
 	maxAgeInDays         = 30
 	enableLogCompression = true
+
+	// defaultErrorChainDepth limits the causes attached by WithError unless set otherwise
+	defaultErrorChainDepth = 10
 )
 
 var (
+	// mainModule is the path of the main module, trimmed from the caller paths
+	mainModule = func() string {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			return info.Main.Path
+		}
+		return ""
+	}()
+
+	// appVersion is rendered in every log line unless overridden by SetVersion. It can be set at build time with
+	// -ldflags "-X github.com/binalyze/logger.appVersion=<version>".
 	appVersion = "1.0.0"
 
-	logger  = logrus.New()
-	logFile = getLogFileName(".log")
+	// callerKeys are the keys of the fields holding caller information
+	callerKeys = []string{"file", "line", "function"}
+
+	// reservedKeys are the keys rendered by the formatter itself which custom fields can't use
+	reservedKeys = map[string]bool{
+		"level":    true,
+		"time":     true,
+		"version":  true,
+		"prefix":   true,
+		"message":  true,
+		"file":     true,
+		"line":     true,
+		"function": true,
+		"caller":   true,
+	}
 )
 
-// Init initiates logger with writer, formatter and level
-func Init() error {
-	logger.Out = getWriter()
-	logger.Formatter = &formatter{}
-	logger.Level = logrus.InfoLevel
+// Logger wraps a logrus instance with its own formatter and rotated file writer. Multiple loggers can be used in one
+// process independently, e.g. one for audit and one for debug logs.
+type Logger struct {
+	// sequence numbers the lines if the sequence field is set, it's accessed atomically and kept first for alignment
+	sequence uint64
+
+	log       *logrus.Logger
+	formatter *formatter
+
+	// callerSkip and noCaller are accessed atomically since they're read on every log call
+	callerSkip int32
+	noCaller   int32
+
+	// functionStyle is the FunctionNameStyle of the function field, accessed atomically
+	functionStyle int32
+
+	// noCallerLevels has the bit 1<<level set for the levels reported without caller, accessed atomically
+	noCallerLevels uint32
+
+	// levelSet is non-zero once the level was set explicitly, which Init keeps, accessed atomically
+	levelSet int32
+
+	// disabled is non-zero while logging is turned off by SetEnabled, accessed atomically
+	disabled int32
+
+	// goroutineID is non-zero if the goroutine ID is added to the lines, accessed atomically
+	goroutineID int32
+
+	// autoStackLevel is the level set by SetAutoStackTrace plus one, zero if it's disabled, accessed atomically
+	autoStackLevel int32
+
+	// errorChainDepth limits the causes attached by WithError, accessed atomically
+	errorChainDepth int32
+
+	// sampler holds the *sampler limiting the log rate, nil if sampling is disabled
+	sampler atomic.Value
+
+	// burst holds the *burstSampler keeping samples of bursts, nil if burst sampling is disabled
+	burst atomic.Value
+
+	// dedup holds the *deduper suppressing consecutive duplicates, nil if deduplication is disabled
+	dedup atomic.Value
+
+	// filters holds the []func(*logrus.Entry) bool added by AddFilter, replaced under filtersMu
+	filters   atomic.Value
+	filtersMu sync.Mutex
+
+	// onLog holds the []LogFunc registered by OnLog, replaced under onLogMu
+	onLog   atomic.Value
+	onLogMu sync.Mutex
+
+	// trimPrefixes holds the []string of prefixes trimmed from caller paths
+	trimPrefixes atomic.Value
+
+	// sequenceField holds the string name of the field numbering the lines, empty if disabled. seqMu serializes
+	// numbering and logging the lines so that they're written in the order of their numbers
+	sequenceField atomic.Value
+	seqMu         sync.Mutex
+
+	// hostPID holds the logrus.Fields of the host and pid fields, nil if disabled
+	hostPID atomic.Value
+
+	// writeErrorHandler holds the func(error) set by SetWriteErrorHandler
+	writeErrorHandler atomic.Value
+
+	// internalErrorHandler holds the func(error) set by SetInternalErrorHandler
+	internalErrorHandler atomic.Value
+
+	// hooksMu serializes changes of the hooks so that Hooks can read them while logrus fires them
+	hooksMu sync.Mutex
+
+	// emptySeparatorWarning warns about an empty line separator only once
+	emptySeparatorWarning sync.Once
+
+	// ctxMu guards the context extractors
+	ctxMu      sync.RWMutex
+	extractors []ContextExtractor
+
+	// mu guards the output configuration and rotatedFile which is swapped when the output is rebuilt
+	mu          sync.Mutex
+	filePath    string
+	noFile      bool
+	console     bool
+	consoleHook *consoleHook
+	output      io.Writer
+	asyncConfig asyncConfig
+	async       *asyncWriter
+	rotation    RotationConfig
+	rotatedFile *lumberjack.Logger
+	compression Compression
+	backups     *backupCompressor
+	routes      []*routeHook
+	counter     *levelCounter
+	lokiHooks   []*lokiHook
+	otlpHooks   []*otlpHook
+	kafkaHooks  []*kafkaHook
+	ring        *ringBuffer
+	tags        *tagHook
+	audits      io.WriteCloser
+	auditPath   string
+	chain       *hashChain
+
+	// sizeRotation is set if MaxSize was configured explicitly, which excludes daily rotation
+	sizeRotation bool
+	daily        bool
+	dailyFile    *dailyFile
+
+	// maintenanceStop stops the maintenance of the rotated files run every maintenanceInterval, nil if it's disabled
+	maintenanceInterval time.Duration
+	maintenanceStop     chan struct{}
+}
+
+// RotationConfig holds the rotation parameters of the log file.
+type RotationConfig struct {
+	// MaxSize is the maximum size in megabytes of the log file before it gets rotated. Zero means lumberjack's default
+	// of 100 megabytes, NoSizeRotation never rotates the file, e.g. when it's rotated by logrotate.
+	MaxSize int
+	// MaxBackups is the maximum number of rotated files to retain. Zero retains all of them.
+	MaxBackups int
+	// MaxAge is the maximum number of days to retain rotated files. Zero retains them regardless of age.
+	MaxAge int
+	// Compress determines if rotated files are compressed, with gzip unless set otherwise by SetCompression.
+	Compress bool
+}
+
+// defaultRotationConfig returns the rotation parameters used unless configured otherwise.
+func defaultRotationConfig() RotationConfig {
+	return RotationConfig{
+		MaxSize:    maxSizeInMBs,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAgeInDays,
+		Compress:   enableLogCompression,
+	}
+}
+
+// New creates a Logger configured with opts. Without options it logs at Info level to the default log file and to the
+// console if LOG_TO_CONSOLE environment variable is set.
+func New(opts ...Option) *Logger {
+	l := &Logger{
+		log:             logrus.New(),
+		errorChainDepth: defaultErrorChainDepth,
+		filePath:        getLogFileName(".log"),
+		console:         os.Getenv(envLogToConsole) != "",
+		rotation:        defaultRotationConfig(),
+	}
+	l.setFormatter(&formatter{})
+	l.log.SetLevel(logrus.InfoLevel)
+	l.consoleHook = newConsoleHook(l)
+	l.log.AddHook(l.consoleHook)
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	l.mu.Lock()
+	l.log.SetOutput(l.getWriter())
+	l.mu.Unlock()
+
+	return l
+}
+
+// init rebuilds the writer according to LOG_TO_CONSOLE environment variable. The formatter is kept, e.g. the format set
+// by LoadFromEnv or the redacted keys, and the level is reset to Info unless it was set explicitly, e.g. by SetLevel,
+// SetDebugLogging or LoadFromEnv, so that both can be configured before Init. It returns an error if the log file
+// can't be created.
+func (l *Logger) init() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.output == nil && !l.noFile {
+		if err := checkLogFile(l.activeFilePath()); err != nil {
+			return err
+		}
+	}
+
+	l.console = os.Getenv(envLogToConsole) != ""
+	l.log.SetOutput(l.getWriter())
+
+	if atomic.LoadInt32(&l.levelSet) == 0 {
+		l.log.SetLevel(logrus.InfoLevel)
+	}
 
 	return nil
 }
 
-// SetPrefix prepends prefix s to the log messages and call it thread safe.
-func SetPrefix(s string) {
-	logger.SetFormatter(&formatter{prefix: s})
+// reset restores the configuration of a logger created by New without options: the default log file, rotation,
+// formatter, level and output, without hooks other than the console, sampling, deduplication, asynchronous writing or
+// context extractors. The current files are closed.
+func (l *Logger) reset() {
+	l.SetDedup(false, 0)
+	l.SetSampling(0, 0)
+	l.SetBurstSampling(0, 0)
+	atomic.StoreInt32(&l.noCaller, 0)
+	atomic.StoreInt32(&l.callerSkip, 0)
+	l.SetFunctionNameStyle(FunctionFull)
+	l.SetCallerLevels()
+	l.DisableAutoStackTrace()
+	l.SetEnabled(true)
+	l.SetErrorChainDepth(defaultErrorChainDepth)
+	l.SetTrimPrefixes()
+	l.SetSequenceField("")
+	l.SetIncludeHostPID(false)
+	l.SetIncludeGoroutineID(false)
+	l.SetWriteErrorHandler(nil)
+	l.filtersMu.Lock()
+	l.filters.Store([]func(*logrus.Entry) bool(nil))
+	l.filtersMu.Unlock()
+	l.onLogMu.Lock()
+	l.onLog.Store([]LogFunc(nil))
+	l.onLogMu.Unlock()
+	atomic.StoreUint64(&l.sequence, 0)
+
+	l.ctxMu.Lock()
+	l.extractors = nil
+	l.ctxMu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_ = l.flush()
+	_ = l.closeRoutes()
+	l.stopMaintenance()
+	l.filePath = getLogFileName(".log")
+	l.noFile = false
+	l.console = os.Getenv(envLogToConsole) != ""
+	l.output = nil
+	l.asyncConfig = asyncConfig{}
+	l.rotation = defaultRotationConfig()
+	l.sizeRotation = false
+	l.daily = false
+	l.compression = CompressionGzip
+	l.routes = nil
+	l.counter = nil
+	l.lokiHooks = nil
+	l.otlpHooks = nil
+	l.kafkaHooks = nil
+	l.ring = nil
+	if l.tags != nil {
+		_ = l.tags.close()
+		l.tags = nil
+	}
+	if l.audits != nil {
+		_ = l.audits.Close()
+		l.audits = nil
+	}
+	l.chain = nil
+	l.internalErrorHandler.Store((func(error))(nil))
+	l.emptySeparatorWarning = sync.Once{}
+
+	l.consoleHook = newConsoleHook(l)
+	l.hooksMu.Lock()
+	l.log.ReplaceHooks(logrus.LevelHooks{})
+	l.log.AddHook(l.consoleHook)
+	l.hooksMu.Unlock()
+	l.log.ExitFunc = os.Exit
+	l.log.SetOutput(l.getWriter())
+
+	l.setFormatter(&formatter{})
+	l.log.SetLevel(logrus.InfoLevel)
+	atomic.StoreInt32(&l.levelSet, 0)
 }
 
-// Debugf logs a message at level Debug on the standard logger.
-func Debugf(format string, args ...interface{}) {
-	if logger.IsLevelEnabled(logrus.DebugLevel) {
-		entry := newEntry()
-		entry.Debugf(format, args...)
+// SetLogFilePath sets the path of the log file and re-initializes the writer so the change takes effect immediately.
+// Parent directories are created if they don't exist. It returns an error if path is a directory or not writable.
+func (l *Logger) SetLogFilePath(path string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	check := path
+	if l.daily {
+		check = dailyFileName(path, time.Now())
+	}
+	if err := checkLogFile(check); err != nil {
+		return err
 	}
+
+	l.filePath = path
+	l.log.SetOutput(l.getWriter())
+
+	return nil
 }
 
-// Infof logs a message at level Info on the standard logger.
-func Infof(format string, args ...interface{}) {
-	entry := newEntry()
-	entry.Infof(format, args...)
+// SetFileLogging enables or disables writing to the log file, enabled by default. When disabled no file is created and
+// the lines are written to the console regardless of LOG_TO_CONSOLE environment variable, split between stderr and
+// stdout as set by SetStdErrThreshold, e.g. for containers whose output is collected.
+func (l *Logger) SetFileLogging(enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.noFile = !enabled
+	l.log.SetOutput(l.getWriter())
 }
 
-// Warnf logs a message at level Warn on the standard logger.
-func Warnf(format string, args ...interface{}) {
-	entry := newEntry()
-	entry.Warnf(format, args...)
+// SetStdErrThreshold sets the level at and above which console lines are written to stderr, lower levels are written
+// to stdout. It's Warn by default so that collectors distinguishing the streams see warnings and errors on stderr.
+func (l *Logger) SetStdErrThreshold(level logrus.Level) {
+	l.consoleHook.setErrLevel(level)
 }
 
-// Errorf logs a message at level Error on the standard logger.
-func Errorf(format string, args ...interface{}) {
-	entry := newEntry()
-	entry.Errorf(format, args...)
+// SetRotationConfig sets the rotation parameters of the log file. Calling it after the logger is initialized swaps
+// the writer live, subsequent logs are written with the new parameters. It returns an error if daily rotation is
+// enabled and cfg sets MaxSize since daily and size-based rotation are mutually exclusive.
+func (l *Logger) SetRotationConfig(cfg RotationConfig) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.daily && cfg.MaxSize != 0 {
+		return fmt.Errorf("size-based rotation of %d MB can't be combined with daily rotation, disable RotateDaily first",
+			cfg.MaxSize)
+	}
+
+	l.rotation = cfg
+	l.sizeRotation = cfg.MaxSize != 0
+	l.log.SetOutput(l.getWriter())
+
+	return nil
 }
 
-// Fatalf logs a message at level Fatal on the standard logger.
-func Fatalf(format string, args ...interface{}) {
-	entry := newEntry()
-	entry.Fatalf(format, args...)
+// SetReportCaller enables or disables the file, line and function fields, enabled by default. When disabled the caller
+// isn't resolved at all which saves the cost of runtime.Callers.
+func (l *Logger) SetReportCaller(enabled bool) {
+	var noCaller int32
+	if !enabled {
+		noCaller = 1
+	}
+
+	atomic.StoreInt32(&l.noCaller, noCaller)
+}
+
+// SetCallerFormat sets how the caller is rendered: CallerFull by default, CallerShort for the base name of the file
+// or CallerNone to omit it. Unlike SetReportCaller(false), CallerNone still resolves the caller, e.g. for sampling and
+// hooks.
+func (l *Logger) SetCallerFormat(c CallerFormat) {
+	l.formatter.setCallerFormat(c)
+}
+
+// SetCallerStyle sets whether the caller is rendered as separate file, line and function fields, CallerSeparate by
+// default, or as a single caller field made of the file and the line with CallerCombined, in both text and JSON. The
+// file is rendered as set by SetCallerFormat.
+func (l *Logger) SetCallerStyle(style CallerStyle) {
+	l.formatter.setCallerStyle(style)
+}
+
+// SetTrimPrefixes sets prefixes trimmed from the caller file paths, e.g. the checkout directory of the build machine.
+// The first matching prefix is trimmed. Without a matching prefix the path is made of the import path of the caller's
+// package without the main module path, e.g. handler/user.go for github.com/acme/app/handler, which only differs for
+// main packages whose import path is unknown.
+func (l *Logger) SetTrimPrefixes(prefixes ...string) {
+	l.trimPrefixes.Store(append([]string(nil), prefixes...))
+}
+
+// SetSequenceField adds the field name numbering every line with an increasing integer starting at 1 with the process,
+// e.g. to detect lines dropped by a pipeline. Concurrent lines get unique numbers and are written in their order, the
+// lines are serialized from numbering them to writing them while it's enabled. A name reserved by the logger, e.g.
+// time, is prefixed with "fields." like custom fields. An empty name disables it, the default.
+func (l *Logger) SetSequenceField(name string) {
+	if reservedKeys[name] {
+		name = "fields." + name
+	}
+
+	l.sequenceField.Store(name)
+}
+
+// SetIncludeHostPID adds the host and pid fields to every line, e.g. to tell apart the lines of multiple hosts once
+// aggregated. Both are resolved once by this call, the host field is omitted if the hostname can't be resolved.
+// Custom fields with the same keys take precedence. Disabled by default.
+func (l *Logger) SetIncludeHostPID(enabled bool) {
+	if !enabled {
+		l.hostPID.Store(logrus.Fields(nil))
+		return
+	}
+
+	fields := logrus.Fields{"pid": os.Getpid()}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		fields["host"] = host
+	}
+	l.hostPID.Store(fields)
+}
+
+// SetCallerSkip sets the number of additional stack frames to skip when resolving the caller. Functions wrapping the
+// logging helpers must bump it by the number of frames between their callers and the helpers, e.g. 1 for a function
+// calling Infof directly, so that the caller of the wrapper is reported instead of the wrapper itself.
+func (l *Logger) SetCallerSkip(n int) {
+	atomic.StoreInt32(&l.callerSkip, int32(n))
+}
+
+// SetSampling limits each call site, identified by the caller file and line, to n messages per window. Messages over
+// the limit are dropped and the first message of the next window carries their count in the dropped field. Fatal and
+// panic messages are never dropped. Sampling is disabled by default, n <= 0 or per <= 0 disables it.
+func (l *Logger) SetSampling(n int, per time.Duration) {
+	if n <= 0 || per <= 0 {
+		l.sampler.Store((*sampler)(nil))
+		return
+	}
+
+	l.sampler.Store(newSampler(n, per))
+}
+
+// SetDedup enables or disables suppressing consecutive identical messages, i.e. messages with the same level, text and
+// caller. The first message is logged and its repetitions are counted, a "last message repeated N times" summary
+// with the original level and caller is logged when a different message arrives, when window expires or on Flush and
+// Close. Deduplication is disabled by default.
+func (l *Logger) SetDedup(enabled bool, window time.Duration) {
+	var d *deduper
+	if enabled {
+		d = newDeduper(window, l.logEntry)
+	}
+
+	if old, _ := l.dedup.Load().(*deduper); old != nil {
+		old.flush()
+	}
+	l.dedup.Store(d)
+}
+
+// SetColorized enables or disables colorizing the level of console output with ANSI escape codes, red for errors,
+// yellow for warnings and so on. The log file is never colorized. Colors are only written if stdout is a terminal
+// unless forced with SetForceColors.
+func (l *Logger) SetColorized(enabled bool) {
+	l.consoleHook.setColorized(enabled)
+}
+
+// SetForceColors makes colorized console output be written even if stdout is not a terminal.
+func (l *Logger) SetForceColors(force bool) {
+	l.consoleHook.setForceColors(force)
+}
+
+// SetAsync makes the output be written by a single background goroutine, so that logging calls don't wait for the
+// file I/O. Up to bufferSize lines are buffered, a full buffer blocks the caller unless WithDropOldest is given.
+// Flush and Close wait for the buffered lines to be written. A bufferSize <= 0 writes the pending lines and disables
+// asynchronous writing. Hooks, including console output, are still run by the caller.
+func (l *Logger) SetAsync(bufferSize int, opts ...AsyncOption) {
+	cfg := asyncConfig{bufferSize: bufferSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.bufferSize < 0 {
+		cfg.bufferSize = 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.asyncConfig = cfg
+	l.log.SetOutput(l.getWriter())
+}
+
+// AsyncDropped returns the number of lines dropped by asynchronous writing with WithDropOldest since it was enabled.
+func (l *Logger) AsyncDropped() uint64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.async == nil {
+		return 0
+	}
+
+	return atomic.LoadUint64(&l.async.dropped)
+}
+
+// SetPrefix prepends prefix s to the log messages. Only the prefix is updated, the other formatter settings are kept,
+// and it's safe to call while logging.
+func (l *Logger) SetPrefix(s string) {
+	l.formatter.setPrefix(s)
+}
+
+// SetFormat sets the encoding of the log lines, FormatText by default. It applies to both the file and the console
+// unless overridden by SetFileFormat or SetConsoleFormat.
+func (l *Logger) SetFormat(format Format) {
+	l.formatter.setFormat(format)
+}
+
+// SetFileFormat sets the encoding of the lines written to the log file, or to the output set by SetOutput, overriding
+// SetFormat.
+func (l *Logger) SetFileFormat(format Format) {
+	l.formatter.setFileFormat(format)
+}
+
+// SetConsoleFormat sets the encoding of the lines written to the console, overriding SetFormat, e.g. text on the
+// console and JSON in the file. Console lines are always formatted separately from the file lines, so enabling console
+// output costs a second formatting of every entry.
+func (l *Logger) SetConsoleFormat(format Format) {
+	l.formatter.setConsoleFormat(format)
+}
+
+// Tracef logs a message at level Trace.
+func (l *Logger) Tracef(format string, args ...interface{}) {
+	l.logf(logrus.TraceLevel, format, args...)
+}
+
+// Debugf logs a message at level Debug.
+func (l *Logger) Debugf(format string, args ...interface{}) {
+	l.logf(logrus.DebugLevel, format, args...)
+}
+
+// Infof logs a message at level Info.
+func (l *Logger) Infof(format string, args ...interface{}) {
+	l.logf(logrus.InfoLevel, format, args...)
+}
+
+// Warnf logs a message at level Warn.
+func (l *Logger) Warnf(format string, args ...interface{}) {
+	l.logf(logrus.WarnLevel, format, args...)
+}
+
+// Errorf logs a message at level Error.
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	l.logf(logrus.ErrorLevel, format, args...)
+}
+
+// Fatalf logs a message at level Fatal and exits.
+func (l *Logger) Fatalf(format string, args ...interface{}) {
+	l.logf(logrus.FatalLevel, format, args...)
+	l.exit()
+}
+
+// Panicf logs a message at level Panic and panics.
+func (l *Logger) Panicf(format string, args ...interface{}) {
+	l.logf(logrus.PanicLevel, format, args...)
+}
+
+// Trace logs a message at level Trace.
+func (l *Logger) Trace(args ...interface{}) {
+	l.logArgs(logrus.TraceLevel, args...)
+}
+
+// Debug logs a message at level Debug.
+func (l *Logger) Debug(args ...interface{}) {
+	l.logArgs(logrus.DebugLevel, args...)
+}
+
+// Info logs a message at level Info.
+func (l *Logger) Info(args ...interface{}) {
+	l.logArgs(logrus.InfoLevel, args...)
+}
+
+// Warn logs a message at level Warn.
+func (l *Logger) Warn(args ...interface{}) {
+	l.logArgs(logrus.WarnLevel, args...)
+}
+
+// Error logs a message at level Error.
+func (l *Logger) Error(args ...interface{}) {
+	l.logArgs(logrus.ErrorLevel, args...)
+}
+
+// Fatal logs a message at level Fatal and exits.
+func (l *Logger) Fatal(args ...interface{}) {
+	l.logArgs(logrus.FatalLevel, args...)
+	l.exit()
+}
+
+// Panic logs a message at level Panic and panics.
+func (l *Logger) Panic(args ...interface{}) {
+	l.logArgs(logrus.PanicLevel, args...)
+}
+
+// Writer returns the underlying io.Writer instance of the logger, including the console if console output is enabled.
+// Each write reaches the console in one piece, not interleaved with log lines.
+func (l *Logger) Writer() io.Writer {
+	// The errors are returned to the caller rather than reported to the internal error handler
+	out := l.log.Out
+	if w, ok := out.(reportingWriter); ok {
+		out = w.Writer
+	}
+
+	if console := l.consoleHook.output(); console != nil {
+		return io.MultiWriter(out, consoleWriter{hook: l.consoleHook, out: console})
+	}
+
+	return out
+}
+
+// SetOutput makes the logger write to w exactly, bypassing the rotated file and console output until ResetOutput is
+// called.
+func (l *Logger) SetOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.output = w
+	l.log.SetOutput(l.getWriter())
+}
+
+// ResetOutput restores the default output, the rotated file and the console if it's enabled, by WithConsole or
+// LOG_TO_CONSOLE environment variable when the logger was created or initialized.
+func (l *Logger) ResetOutput() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.output = nil
+	l.log.SetOutput(l.getWriter())
+}
+
+// Flush flushes writes buffered by the logger and the output. It waits for pending asynchronous writes and the
+// batches of the Loki, OpenTelemetry and Kafka hooks, the rotated file doesn't buffer and custom outputs set by
+// SetOutput are flushed if they have a Flush method, e.g. *bufio.Writer.
+func (l *Logger) Flush() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.flush()
+}
+
+// Close flushes the output, stops the maintenance of the rotated files and closes the rotated file and the files of
+// routed levels, tags and audit lines. Logging after Close is safe, the files are reopened lazily on the next write.
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err := l.flush()
+	l.stopMaintenance()
+	if closeErr := l.closeFiles(); err == nil {
+		err = closeErr
+	}
+
+	return err
 }
 
-// Writer returns the underlying io.Writer instance of the logger.
-func Writer() io.Writer {
-	return logger.Out
+// closeFiles closes the rotated file and the files of routed levels, tags and audit lines, which are reopened lazily
+// on the next write, callers must hold l.mu.
+func (l *Logger) closeFiles() error {
+	var err error
+	if l.rotatedFile != nil {
+		err = l.rotatedFile.Close()
+	}
+	if l.dailyFile != nil {
+		if closeErr := l.dailyFile.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if closeErr := l.closeRoutes(); err == nil {
+		err = closeErr
+	}
+	if l.tags != nil {
+		if closeErr := l.tags.close(); err == nil {
+			err = closeErr
+		}
+	}
+	if l.audits != nil {
+		if closeErr := l.audits.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
 }
 
 // SetDebugLogging sets the logging level
-func SetDebugLogging(enabled bool) {
-	logger.Infof("Debug logging set to: %t", enabled)
+func (l *Logger) SetDebugLogging(enabled bool) {
+	l.log.Infof("Debug logging set to: %t", enabled)
 
 	if enabled {
-		logger.SetLevel(logrus.DebugLevel)
+		l.SetLevelL(logrus.DebugLevel)
 		return
 	}
 
 	// If not enabled, set to default info level
-	logger.SetLevel(logrus.InfoLevel)
+	l.SetLevelL(logrus.InfoLevel)
+}
+
+// SetLevel parses level, e.g. "warn" or "trace", and sets it as the logging level. It returns an error for unknown
+// levels.
+func (l *Logger) SetLevel(level string) error {
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	l.SetLevelL(parsed)
+
+	return nil
+}
+
+// SetLevelL sets the logging level. The summaries of sampled bursts are written out first at the level they were
+// sampled at. A level set before Init is kept by Init.
+func (l *Logger) SetLevelL(level logrus.Level) {
+	l.flushBurst()
+	l.log.SetLevel(level)
+	atomic.StoreInt32(&l.levelSet, 1)
+}
+
+// GetLevel returns the logger instance's log level.
+func (l *Logger) GetLevel() logrus.Level {
+	return l.log.GetLevel()
+}
+
+// LevelString returns the name of the log level as accepted by SetLevel, e.g. "info" or "warning".
+func (l *Logger) LevelString() string {
+	return l.log.GetLevel().String()
+}
+
+// IsDebugEnabled reports whether Debug messages are logged, e.g. to skip building expensive debug output. It's false
+// while logging is disabled by SetEnabled.
+func (l *Logger) IsDebugEnabled() bool {
+	return l.isLevelEnabled(logrus.DebugLevel)
+}
+
+// IsTraceEnabled reports whether Trace messages are logged.
+func (l *Logger) IsTraceEnabled() bool {
+	return l.isLevelEnabled(logrus.TraceLevel)
+}
+
+// SetVersion sets the application version rendered in log lines, e.g. a git SHA stamped at build time. An empty
+// version restores the build time default.
+func (l *Logger) SetVersion(v string) {
+	l.formatter.setVersion(v)
+}
+
+// SetTimeFormat sets the layout of the timestamps, e.g. time.Kitchen. An empty layout restores the default RFC3339
+// with the precision set by SetTimePrecision.
+func (l *Logger) SetTimeFormat(layout string) {
+	l.formatter.setTimeFormat(layout)
+}
+
+// SetTimePrecision sets the fraction of a second rendered in the default RFC3339 timestamps, milliseconds by default so
+// that lines logged within the same second keep their order. A layout set by SetTimeFormat takes precedence.
+func (l *Logger) SetTimePrecision(p TimePrecision) {
+	l.formatter.setTimePrecision(p)
+}
+
+// SetReportTimestamp enables or disables the time of the log lines, enabled by default. Disabling it avoids a second
+// timestamp where the collector stamps the lines itself, e.g. journald or container runtimes, leaving
+// "INFO 1.0.0 message" in text format.
+func (l *Logger) SetReportTimestamp(enabled bool) {
+	l.formatter.setReportTimestamp(enabled)
+}
+
+// SetReportVersion enables or disables the version of the log lines at levels, at all levels if none are given. It's
+// enabled by default. Disabling it at all levels leaves "INFO <time> message <caller>" in text format, disabling it at
+// some levels, e.g. SetReportVersion(false, logrus.DebugLevel, logrus.TraceLevel), drops it where it's noise.
+func (l *Logger) SetReportVersion(enabled bool, levels ...logrus.Level) {
+	l.formatter.setReportVersion(enabled, levels...)
 }
 
-// GetLevel returns the logger instance's log level and exported for testing purposes to determine log level is set
-// correctly.
-func GetLevel() logrus.Level {
-	return logger.GetLevel()
+// SetTimeZone sets the location the timestamps are rendered in, local time by default. It returns an error if loc is
+// nil.
+func (l *Logger) SetTimeZone(loc *time.Location) error {
+	if loc == nil {
+		return errors.New("time zone location is nil")
+	}
+
+	l.formatter.setTimeZone(loc)
+
+	return nil
 }
 
-// newEntry creates new logrus Entry with logrus fields, file, line and function
-func newEntry() *logrus.Entry {
-	file, function, line := callerInfo(skipFrameCount, splitAfterPkgName)
+// flush writes out the pending burst sampling and deduplication summaries, asynchronous writes and the batches of the
+// Loki, OpenTelemetry and Kafka hooks and flushes the custom output if it buffers writes, callers must hold l.mu.
+func (l *Logger) flush() error {
+	l.flushBurst()
+	if d, _ := l.dedup.Load().(*deduper); d != nil {
+		d.flush()
+	}
+
+	if l.async != nil {
+		l.async.flush()
+	}
+	l.flushLoki()
+	l.flushOTLP()
+	l.flushKafka()
+
+	if f, ok := l.output.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+
+	return nil
+}
+
+// SetExitFunc sets the function called with status 1 by the Fatal helpers after the output is flushed, os.Exit by
+// default, e.g. to run cleanup before exiting or to panic in tests. A nil fn restores os.Exit. It's meant to be set
+// at startup, not concurrently with fatal logs.
+func (l *Logger) SetExitFunc(fn func(int)) {
+	if fn == nil {
+		fn = os.Exit
+	}
+
+	l.log.ExitFunc = fn
+}
+
+// SetFormatter replaces the built-in layouts of the log file and console lines with f, e.g. an existing
+// &logrus.JSONFormatter{}. The caller is passed to f in the "file", "line" and "function" fields of the entry, the
+// custom fields as they are. Level names, line separator, prefix, version, time and redaction settings only apply to
+// the built-in layouts, DefaultFormatter can be used to delegate to them. A nil f restores the built-in layouts.
+// Reinit restores the built-in layouts too.
+func (l *Logger) SetFormatter(f logrus.Formatter) {
+	l.formatter.setCustom(f)
+}
+
+// DefaultFormatter returns the built-in formatter of the logger, rendering the log file layout regardless of
+// SetFormatter, so that custom formatters can wrap or delegate to it.
+func (l *Logger) DefaultFormatter() logrus.Formatter {
+	return builtinFormatter{logger: l}
+}
+
+// SetLineSeparator sets the separator terminating every line, e.g. "\x00" for NUL delimited records or "\n" to write
+// Unix line endings on Windows. It defaults to "\r\n" on Windows and "\n" elsewhere. An empty separator is allowed
+// but concatenates the lines, a warning is logged the first time it's set.
+func (l *Logger) SetLineSeparator(sep string) {
+	if sep == "" {
+		l.emptySeparatorWarning.Do(func() {
+			l.log.Warnf("Empty line separator set, log lines will be concatenated")
+		})
+	}
+
+	l.formatter.setLineSeparator(sep)
+}
+
+// SetLevelNames sets the tokens rendered for the levels in names in text and JSON lines, e.g. WARN instead of the
+// default WARNING. The other levels keep their default token: PANIC, FATAL, ERROR, WARNING, INFO, DEBUG and TRACE.
+// A nil map restores the defaults.
+func (l *Logger) SetLevelNames(names map[logrus.Level]string) {
+	l.formatter.setLevelNames(names)
+}
+
+// SetFieldOrder sets the segments of the text layout in order, e.g. FieldTime, FieldLevel and FieldMessage for
+// "2021-01-26T14:37:17.123+03:00 INFO message", the segments left out aren't rendered. The custom fields always come
+// last. The default layout is FieldLevel, FieldTime, FieldVersion, FieldPrefix, FieldMessage and FieldCaller, which a
+// nil order restores. It returns an error if a segment is unknown or repeated or the message is left out.
+func (l *Logger) SetFieldOrder(order []string) error {
+	if order == nil {
+		l.formatter.setFieldOrder(nil)
+		return nil
+	}
+
+	seen := make(map[string]bool, len(order))
+	for _, field := range order {
+		if !textFields[field] {
+			return fmt.Errorf("unknown field %q", field)
+		}
+		if seen[field] {
+			return fmt.Errorf("field %q is repeated", field)
+		}
+		seen[field] = true
+	}
+	if !seen[FieldMessage] {
+		return errors.New("field order must include the message")
+	}
+
+	l.formatter.setFieldOrder(append([]string(nil), order...))
+	return nil
+}
+
+// SetJSONFieldNames renames the fields of the JSON layout, e.g. {"time": "@timestamp", "level": "log.level"} for an
+// index expecting Elastic Common Schema names. The keys are the names of the layout: level, time, version, prefix,
+// message, caller, file, line and function. Custom fields named like a renamed field are prefixed with "fields.".
+// Calling it again replaces the names, a nil map restores the defaults. It returns an error if a key is unknown, a
+// name is empty or two fields would be rendered under the same name.
+func (l *Logger) SetJSONFieldNames(names map[string]string) error {
+	if names == nil {
+		l.formatter.setJSONFieldNames(nil)
+		return nil
+	}
+
+	rendered := make(map[string]string, len(jsonKeys))
+	for key := range jsonKeys {
+		if _, ok := names[key]; !ok {
+			rendered[key] = key
+		}
+	}
+	keys := make([]string, 0, len(names))
+	for key := range names {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		name := names[key]
+		if !jsonKeys[key] {
+			return fmt.Errorf("unknown JSON field %q", key)
+		}
+		if name == "" {
+			return fmt.Errorf("JSON field %q can't be renamed to an empty name", key)
+		}
+		if other, ok := rendered[name]; ok {
+			return fmt.Errorf("JSON fields %q and %q can't both be named %q", other, key, name)
+		}
+		rendered[name] = key
+	}
+
+	copied := make(map[string]string, len(names))
+	for key, name := range names {
+		copied[key] = name
+	}
+	l.formatter.setJSONFieldNames(copied)
+
+	return nil
+}
+
+// SetRedactedKeys masks the values of the fields with keys, matched case-insensitively, as *** in both text and JSON
+// formats. Calling it again replaces the keys, calling it without keys disables field redaction.
+func (l *Logger) SetRedactedKeys(keys ...string) {
+	l.formatter.setRedactedKeys(keys...)
+}
+
+// SetRedactPattern masks the matches of re in messages as ***, e.g. credit card numbers. A nil re disables message
+// redaction.
+func (l *Logger) SetRedactPattern(re *regexp.Regexp) {
+	l.formatter.setRedactPattern(re)
+}
+
+// SetMaxMessageLength truncates messages longer than n runes to n runes followed by a "...[truncated N bytes]" marker,
+// e.g. to keep a serialized payload from blowing the rotation budget. Fields aren't truncated. It applies to the file,
+// the console and hooks rendering the message, custom formatters set by SetFormatter receive the message as is. Zero or
+// less disables truncation, the default.
+func (l *Logger) SetMaxMessageLength(n int) {
+	l.formatter.setMaxMessageLength(n)
+}
+
+// SetEscapeControlChars enables or disables escaping the control characters of messages and field keys in the text
+// layout, disabled by default. Newlines, carriage returns and tabs are written as \n, \r and \t and the other control
+// characters as \x1b or \u0085, so that user supplied input can't break lines apart, forge lines or smuggle ANSI
+// sequences to terminals. Field values are always quoted and escaped if needed, the JSON layout escapes everything.
+func (l *Logger) SetEscapeControlChars(enabled bool) {
+	l.formatter.setEscapeControlChars(enabled)
+}
 
-	entry := logger.WithFields(logrus.Fields{})
-	entry.Data["file"] = file
-	entry.Data["line"] = line
-	entry.Data["function"] = function
+// setFormatter installs f as the formatter of the logger.
+func (l *Logger) setFormatter(f *formatter) {
+	l.formatter = f
+	l.log.SetFormatter(reportingFormatter{formatter: f, logger: l})
+}
+
+// logf logs a formatted message at level with caller information. The caller is resolved only if level is enabled.
+// Exported helpers must call it directly so that the caller frame is always skipFrameCount frames away.
+func (l *Logger) logf(level logrus.Level, format string, args ...interface{}) {
+	if !l.isLevelEnabled(level) {
+		return
+	}
+
+	if entry := l.newEntry(0, level, nil, true); entry != nil {
+		l.write(entry, level, fmt.Sprintf(format, args...))
+	}
+}
+
+// logArgs logs args at level with caller information, args are handled as in fmt.Sprint. The caller is resolved only
+// if level is enabled. Exported helpers must call it directly so that the caller frame is always skipFrameCount frames
+// away.
+func (l *Logger) logArgs(level logrus.Level, args ...interface{}) {
+	if !l.isLevelEnabled(level) {
+		return
+	}
+
+	if entry := l.newEntry(0, level, nil, true); entry != nil {
+		l.write(entry, level, fmt.Sprint(args...))
+	}
+}
+
+// write logs msg with entry at level unless a filter drops it, suppressing consecutive duplicates if deduplication is
+// enabled.
+func (l *Logger) write(entry *logrus.Entry, level logrus.Level, msg string) {
+	if l.filtered(entry, level, msg) {
+		return
+	}
+	if d, _ := l.dedup.Load().(*deduper); d != nil && !d.add(entry, level, msg) {
+		return
+	}
+
+	l.logEntry(entry, level, msg)
+}
+
+// logEntry logs msg with entry at level, numbered with the sequence field and with the host and pid fields if they're
+// enabled.
+func (l *Logger) logEntry(entry *logrus.Entry, level logrus.Level, msg string) {
+	if name, _ := l.sequenceField.Load().(string); name != "" {
+		l.seqMu.Lock()
+		defer l.seqMu.Unlock()
+
+		entry.Data[name] = atomic.AddUint64(&l.sequence, 1)
+	}
+	if hostPID, _ := l.hostPID.Load().(logrus.Fields); hostPID != nil {
+		for key, value := range hostPID {
+			if _, ok := entry.Data[key]; !ok {
+				entry.Data[key] = value
+			}
+		}
+	}
+
+	l.notify(entry, level, msg)
+	entry.Log(level, msg)
+}
+
+// newEntry creates new logrus Entry with custom fields, file, line and function. Custom fields using a reserved key
+// are prefixed with "fields." so they can't clobber the fields set by the logger. depth is the number of frames between
+// the helper and the caller in addition to skipFrameCount, e.g. of the log package for StdLogger. The caller fields are
+// omitted if caller is false, e.g. for Entry.NoCaller, or at levels excluded by SetCallerLevels. It returns nil if the
+// entry is dropped by sampling.
+func (l *Logger) newEntry(depth int, level logrus.Level, fields logrus.Fields, caller bool) *logrus.Entry {
+	return l.newEntryAt(depth+1, 0, level, fields, caller)
+}
+
+// newEntryAt creates a new logrus Entry like newEntry, with the caller resolved from the program counter pc if it's
+// not zero, e.g. the one recorded by slog, instead of counting depth frames.
+func (l *Logger) newEntryAt(depth int, pc uintptr, level logrus.Level, fields logrus.Fields,
+	caller bool) *logrus.Entry {
+	// Built directly rather than with logrus' WithFields, which allocates a second entry and map, sized for the caller
+	entry := &logrus.Entry{Logger: l.log, Data: make(logrus.Fields, len(fields)+callerFieldCount)}
+	for key, value := range fields {
+		if reservedKeys[key] {
+			key = "fields." + key
+		}
+		entry.Data[key] = value
+	}
+	if atomic.LoadInt32(&l.goroutineID) != 0 {
+		if _, ok := entry.Data[goroutineIDKey]; !ok {
+			if id := goroutineID(); id != 0 {
+				entry.Data[goroutineIDKey] = id
+			}
+		}
+	}
+
+	reportCaller := caller && atomic.LoadInt32(&l.noCaller) == 0 && l.callerLevel(level)
+	withStack := l.autoStackTrace(level)
+	sampling, _ := l.sampler.Load().(*sampler)
+	burst, _ := l.burst.Load().(*burstSampler)
+	sampled := (sampling != nil || burst != nil) && level > logrus.FatalLevel
+	if !reportCaller && !withStack && !sampled {
+		return entry
+	}
+
+	skip := skipFrameCount + depth + int(atomic.LoadInt32(&l.callerSkip))
+	prefixes, _ := l.trimPrefixes.Load().([]string)
+	short := atomic.LoadInt32(&l.functionStyle) == int32(FunctionShort)
+	var file, function string
+	var line int
+	if pc != 0 {
+		file, function, line = frameInfo([]uintptr{pc}, splitAfterPkgName, prefixes, short)
+	} else {
+		file, function, line = callerInfo(skip, splitAfterPkgName, prefixes, short)
+	}
+
+	// Fatal and panic entries are never dropped so that they still exit and panic
+	if sampling != nil && level > logrus.FatalLevel {
+		allowed, dropped := sampling.allow(file+":"+strconv.Itoa(line), time.Now())
+		if !allowed {
+			return nil
+		}
+		if dropped > 0 {
+			entry.Data["dropped"] = dropped
+		}
+	}
+
+	if reportCaller {
+		entry.Data["file"] = file
+		entry.Data["line"] = line
+		entry.Data["function"] = function
+	}
+	if burst != nil && level > logrus.FatalLevel {
+		allowed, dropped := burst.allow(file+":"+strconv.Itoa(line), entry, level)
+		if !allowed {
+			return nil
+		}
+		if dropped > 0 {
+			windowDropped, _ := entry.Data["dropped"].(int)
+			entry.Data["dropped"] = windowDropped + dropped
+		}
+	}
+	if _, ok := entry.Data["stack"]; withStack && !ok {
+		entry.Data["stack"] = callerStack(skip, pc, prefixes)
+	}
 	return entry
 }
 
-// callerInfo grabs caller file, function and line number
-func callerInfo(skip int, pkgName string) (file, function string, line int) {
+// callerInfo grabs caller file, function and line number, the function is shortened if short is set
+func callerInfo(skip int, pkgName string, prefixes []string, short bool) (file, function string, line int) {
 
 	// Grab frame
 	pc := make([]uintptr, 1)
 	n := runtime.Callers(skip, pc)
-	frames := runtime.CallersFrames(pc[:n])
+
+	return frameInfo(pc[:n], pkgName, prefixes, short)
+}
+
+// frameInfo returns the file, function and line number of the first frame of the program counters pc, the function
+// is shortened if short is set
+func frameInfo(pc []uintptr, pkgName string, prefixes []string, short bool) (file, function string, line int) {
+	frames := runtime.CallersFrames(pc)
 	frame, _ := frames.Next()
 
 	// Set file, function and line number
-	file = trimPkgName(frame.File, pkgName)
+	file = trimPkgName(callerPath(frame.File, frame.Function, prefixes), pkgName)
 	function = trimPkgName(frame.Function, pkgName)
+	if short {
+		function = shortFunctionName(frame.Function)
+	}
 	line = frame.Line
 
 	return
 }
 
+// callerPath returns a path of file independent of the build machine. The first of prefixes file starts with is
+// trimmed. Otherwise the directory is replaced with the import path of the package of function, e.g.
+// github.com/acme/app/handler/user.go, and the path of the main module is trimmed, e.g. handler/user.go. Files of main
+// packages keep their path since it can't be derived from the function.
+func callerPath(file, function string, prefixes []string) string {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(file, prefix) {
+			return strings.TrimPrefix(file[len(prefix):], "/")
+		}
+	}
+
+	if pkg := packagePath(function); pkg != "" && pkg != "main" {
+		file = strings.TrimSuffix(pkg, "_test") + "/" + path.Base(file)
+	}
+	if mainModule != "" && strings.HasPrefix(file, mainModule+"/") {
+		file = file[len(mainModule)+1:]
+	}
+
+	return file
+}
+
+// packagePath returns the import path of the package of function, e.g. github.com/acme/app/db for
+// github.com/acme/app/db.(*Pool).Get.
+func packagePath(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[lastSlash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+
+	return function[:lastSlash+1+dot]
+}
+
 // trimPkgName trims string after splitStr
 func trimPkgName(frameStr, splitStr string) string {
 	count := strings.LastIndex(frameStr, splitStr)
@@ -142,83 +1203,151 @@ func trimPkgName(frameStr, splitStr string) string {
 	return frameStr
 }
 
-func getWriter() io.Writer {
-	logToConsole := os.Getenv(envLogToConsole) != ""
+// getWriter builds the output writer, wrapped for asynchronous writing if enabled and reporting the failed writes to
+// the internal error handler, from the goroutine writing them if asynchronous. Callers must hold l.mu.
+func (l *Logger) getWriter() io.Writer {
+	// Pending asynchronous writes go to the current writer before it's replaced
+	if l.async != nil {
+		l.async.stop()
+		l.async = nil
+	}
+
+	w := l.baseWriter()
+	if l.asyncConfig.bufferSize > 0 {
+		l.async = newAsyncWriter(reportingWriter{Writer: w, logger: l}, l.asyncConfig)
+		w = l.async
+	}
 
-	// Set output according to environment variable
-	var output io.Writer
-	if logToConsole {
-		output = io.MultiWriter(getRotatedFile(), os.Stdout)
+	return reportingWriter{Writer: w, logger: l}
+}
+
+// baseWriter builds the output writer, callers must hold l.mu.
+func (l *Logger) baseWriter() io.Writer {
+	// Custom output bypasses the rotated file and console entirely
+	if l.output != nil {
+		l.closeRotatedFile()
+		l.consoleHook.setOutput(nil)
+		l.consoleHook.setErrOutput(nil)
+		return l.output
+	}
+
+	// Without the file everything goes to the console
+	if l.noFile {
+		l.closeRotatedFile()
+		l.consoleHook.setOutput(os.Stdout)
+		l.consoleHook.setErrOutput(os.Stderr)
+		return ioutil.Discard
+	}
+
+	// Console is written by the console hook so that it can be formatted separately from the file
+	if l.console {
+		l.consoleHook.setOutput(os.Stdout)
+		l.consoleHook.setErrOutput(os.Stderr)
 	} else {
-		output = getRotatedFile()
+		l.consoleHook.setOutput(nil)
+		l.consoleHook.setErrOutput(nil)
 	}
 
-	return output
+	return l.getRotatedFile()
 }
 
+// getLogFileName returns the name of the executable with its extension replaced by extension.
 func getLogFileName(extension string) string {
-	appName := filepath.Base(os.Args[0])
-	ext := filepath.Ext(appName)
-	fmt.Println(ext)
-	if len(ext) > 0 {
-		return strings.Replace(appName, ext, extension, 1)
+	var executable string
+	if len(os.Args) > 0 {
+		executable = os.Args[0]
 	}
 
-	// No extension in filename
-	return appName + extension
+	return logFileName(executable, extension)
 }
 
-// getRotatedFile sets the output to desired file
-func getRotatedFile() io.Writer {
-	return &lumberjack.Logger{
-		Filename:   logFile,
-		MaxSize:    maxSizeInMBs,
-		MaxBackups: maxBackups,
-		MaxAge:     maxAgeInDays,
-		Compress:   enableLogCompression,
+// logFileName returns the base name of executable with its trailing extension, if any, replaced by extension.
+func logFileName(executable, extension string) string {
+	if executable == "" {
+		return defaultAppName + extension
 	}
+
+	appName := filepath.Base(executable)
+	return strings.TrimSuffix(appName, filepath.Ext(appName)) + extension
 }
 
-// Formatter implements logrus.Formatter interface.
-type formatter struct {
-	prefix string
+// getRotatedFile sets the output to desired file and closes the previous one. The file is watched so that it's recreated
+// if removed.
+func (l *Logger) getRotatedFile() io.Writer {
+	l.closeRotatedFile()
+
+	if l.daily {
+		daily := newDailyFile(l.filePath, l.rotation.MaxAge, time.Now)
+		l.dailyFile = daily
+		return &watchedFile{
+			Writer: daily,
+			file:   daily,
+			name:   func() string { return dailyFileName(daily.filename, daily.now()) },
+			logger: l,
+		}
+	}
+
+	file := &lumberjack.Logger{
+		Filename:   l.filePath,
+		MaxSize:    l.rotation.MaxSize,
+		MaxBackups: l.rotation.MaxBackups,
+		MaxAge:     l.rotation.MaxAge,
+		Compress:   l.rotation.Compress,
+	}
+	l.rotatedFile = file
+	watched := &watchedFile{Writer: file, file: file, name: func() string { return file.Filename }, logger: l}
+
+	// Other algorithms than gzip compress the files once lumberjack rotated them
+	if c, ok := compressors[l.compression]; ok && l.rotation.Compress {
+		file.Compress = false
+		l.backups = newBackupCompressor(c, l.filePath, l.rotation)
+		watched.Writer = &compressedFile{Writer: file, compressor: l.backups}
+	}
+
+	return watched
 }
 
-// Format building log message.
-func (f *formatter) Format(entry *logrus.Entry) ([]byte, error) {
-	var sb bytes.Buffer
+// closeRotatedFile closes the current rotated file if there is one, callers must hold l.mu.
+func (l *Logger) closeRotatedFile() {
+	if l.rotatedFile != nil {
+		_ = l.rotatedFile.Close()
+		l.rotatedFile = nil
+	}
+	if l.backups != nil {
+		l.backups.close()
+		l.backups = nil
+	}
+	if l.dailyFile != nil {
+		_ = l.dailyFile.Close()
+		l.dailyFile = nil
+	}
+}
 
-	var newLine = "\n"
-	if runtime.GOOS == "windows" {
-		newLine = "\r\n"
+// activeFilePath returns the path of the file currently written, the file of the day if rotated daily, callers must
+// hold l.mu.
+func (l *Logger) activeFilePath() string {
+	if l.daily {
+		return dailyFileName(l.filePath, time.Now())
 	}
 
-	sb.WriteString(strings.ToUpper(entry.Level.String()))
-	sb.WriteString(" ")
-	sb.WriteString(entry.Time.Format(time.RFC3339))
-	sb.WriteString(" ")
-	sb.WriteString(appVersion)
-	sb.WriteString(" ")
-	sb.WriteString(f.prefix)
-	sb.WriteString(entry.Message)
-	sb.WriteString(" ")
-	file, ok := entry.Data["file"].(string)
-	if ok {
-		sb.WriteString("file:")
-		sb.WriteString(file)
+	return l.filePath
+}
+
+// checkLogFile creates parent directories of path and makes sure path is a writable file
+func checkLogFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
 	}
-	line, ok := entry.Data["line"].(int)
-	if ok {
-		sb.WriteString(":")
-		sb.WriteString(strconv.Itoa(line))
+
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		return fmt.Errorf("log file path %s is a directory", path)
 	}
-	function, ok := entry.Data["function"].(string)
-	if ok {
-		sb.WriteString(" ")
-		sb.WriteString("func:")
-		sb.WriteString(function)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("log file is not writable: %w", err)
 	}
-	sb.WriteString(newLine)
 
-	return sb.Bytes(), nil
+	return f.Close()
 }
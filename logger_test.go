@@ -3,12 +3,16 @@ package logger
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -49,7 +53,7 @@ func TestFormatter(t *testing.T) {
 	// Example expected: "DEBUG 2021-01-23T14:43:03+03:00 1.0.0 Test Message main.go:33\n"
 	expected := fmt.Sprintf("%s %s %s %s %s",
 		convertLevel(data.Level),
-		data.Time.Format(time.RFC3339),
+		data.Time.Format("2006-01-02T15:04:05.000Z07:00"),
 		appVersion,
 		data.Message,
 		data.File,
@@ -66,11 +70,11 @@ func TestSetOutputFile(t *testing.T) {
 	defer func() {
 		os.Remove(f.Name())
 	}()
-	logFile = f.Name()
+	defer ResetForTest()
+	std.filePath = f.Name()
 
-	err = Init()
+	err = Reinit()
 	require.NoError(t, err)
-	logger.Out = getWriter()
 
 	message := randStringBytes(30)
 
@@ -94,8 +98,10 @@ func TestSetOutputConsole(t *testing.T) {
 	}()
 
 	// Mock data
-	logFile = f.Name()
+	defer ResetForTest()
+	std.filePath = f.Name()
 	os.Setenv(envLogToConsole, "true")
+	defer os.Unsetenv(envLogToConsole)
 	message := randStringBytes(30)
 
 	// Redirect stdout to pipe
@@ -104,12 +110,11 @@ func TestSetOutputConsole(t *testing.T) {
 	os.Stdout = w
 
 	// Init with os.Stdout and file as writer
-	err = Init()
+	err = Reinit()
 	require.NoError(t, err)
-	logger.Out = getWriter()
 
-	// Log random generated message
-	Errorf("%s", message)
+	// Log random generated message, below the stderr threshold
+	Infof("%s", message)
 
 	// Catch stdout content from pipe
 	outC := make(chan string)
@@ -144,24 +149,18 @@ func TestLogFatal(t *testing.T) {
 		os.Remove(f.Name())
 	}()
 	message := randStringBytes(30)
-	logFile = f.Name()
+	defer ResetForTest()
+	std.filePath = f.Name()
 
-	err = Init()
+	err = Reinit()
 	require.NoError(t, err)
 
-	logger.Out = getWriter()
-
-	old := logger.ExitFunc
-	defer func() {
-		logger.ExitFunc = old
-	}()
-
 	var exitCode int
 	exitter := func(code int) {
 		exitCode = code
 	}
 
-	logger.ExitFunc = exitter
+	std.log.ExitFunc = exitter
 
 	Fatalf(message)
 
@@ -174,6 +173,41 @@ func TestLogFatal(t *testing.T) {
 
 }
 
+func TestSetExitFunc(t *testing.T) {
+
+	// Mock data
+	f, err := ioutil.TempFile("", "_logger_set_output_*")
+	require.NoError(t, err)
+	defer func() {
+		os.Remove(f.Name())
+	}()
+	message := randStringBytes(30)
+	defer ResetForTest()
+	std.filePath = f.Name()
+
+	err = Reinit()
+	require.NoError(t, err)
+
+	var exitCode int
+	SetExitFunc(func(code int) {
+		exitCode = code
+	})
+	SetAsync(16)
+
+	Fatalf("%s", message)
+
+	require.Equal(t, 1, exitCode)
+
+	// The asynchronous buffer is flushed before exiting
+	content, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Contains(t, string(content), message)
+
+	// Nil restores os.Exit
+	SetExitFunc(nil)
+	require.NotNil(t, std.log.ExitFunc)
+}
+
 func TestLoggerHelpersDebugDisabled(t *testing.T) {
 
 	f, err := ioutil.TempFile("", "_logger_set_output_*")
@@ -183,13 +217,13 @@ func TestLoggerHelpersDebugDisabled(t *testing.T) {
 	}()
 
 	// Mock data
-	logFile = f.Name()
+	defer ResetForTest()
+	std.filePath = f.Name()
 	os.Unsetenv(envLogToConsole)
 
-	err = Init()
+	err = Reinit()
 	require.NoError(t, err)
 
-	logger.Out = getWriter()
 	SetDebugLogging(false)
 
 	messageDebug := randStringBytes(30)
@@ -237,12 +271,12 @@ func TestLoggerHelpersDebugEnabled(t *testing.T) {
 	}()
 
 	// Mock data
-	logFile = f.Name()
+	defer ResetForTest()
+	std.filePath = f.Name()
 	os.Unsetenv(envLogToConsole)
 
-	err = Init()
+	err = Reinit()
 	require.NoError(t, err)
-	logger.Out = getWriter()
 	SetDebugLogging(true)
 
 	messageDebug := randStringBytes(30)
@@ -283,17 +317,840 @@ func TestLoggerHelpersDebugEnabled(t *testing.T) {
 
 }
 
+func TestLoggerHelpersTraceEnabled(t *testing.T) {
+
+	f, err := ioutil.TempFile("", "_logger_set_output_*")
+	require.NoError(t, err)
+	defer func() {
+		os.Remove(f.Name())
+	}()
+
+	// Mock data
+	defer ResetForTest()
+	std.filePath = f.Name()
+	os.Unsetenv(envLogToConsole)
+
+	err = Reinit()
+	require.NoError(t, err)
+	std.log.SetLevel(logrus.TraceLevel)
+
+	messageTrace := randStringBytes(30)
+	messageDebug := randStringBytes(30)
+	messagePanic := randStringBytes(30)
+
+	Tracef("%s", messageTrace)
+	Debugf("%s", messageDebug)
+	require.Panics(t, func() {
+		Panicf("%s", messagePanic)
+	})
+
+	scanner := bufio.NewScanner(f)
+	count := 0
+	for scanner.Scan() {
+		split := strings.Split(scanner.Text(), " ")
+		switch count {
+		case 0:
+			require.Equal(t, "TRACE", split[0])
+			require.Equal(t, messageTrace, split[3])
+		case 1:
+			require.Equal(t, "DEBUG", split[0])
+			require.Equal(t, messageDebug, split[3])
+		case 2:
+			require.Equal(t, "PANIC", split[0])
+			require.Equal(t, messagePanic, split[3])
+		}
+
+		count++
+	}
+	require.Equal(t, 3, count)
+
+	if err := scanner.Err(); err != nil {
+		t.Errorf("Bufio scanner error: %v", err)
+	}
+
+}
+
+func TestLoggerHelpersTraceDisabled(t *testing.T) {
+
+	f, err := ioutil.TempFile("", "_logger_set_output_*")
+	require.NoError(t, err)
+	defer func() {
+		os.Remove(f.Name())
+	}()
+
+	// Mock data
+	defer ResetForTest()
+	std.filePath = f.Name()
+	os.Unsetenv(envLogToConsole)
+
+	err = Reinit()
+	require.NoError(t, err)
+	SetDebugLogging(true)
+
+	Tracef("%s", randStringBytes(30))
+
+	content, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "TRACE")
+}
+
+func TestLoggerHelpersNonFormatted(t *testing.T) {
+
+	f, err := ioutil.TempFile("", "_logger_set_output_*")
+	require.NoError(t, err)
+	defer func() {
+		os.Remove(f.Name())
+	}()
+
+	// Mock data
+	defer ResetForTest()
+	std.filePath = f.Name()
+	os.Unsetenv(envLogToConsole)
+
+	err = Reinit()
+	require.NoError(t, err)
+	std.log.SetLevel(logrus.DebugLevel)
+
+	messageTrace := randStringBytes(30)
+	messageDebug := randStringBytes(30)
+	messageInfo := randStringBytes(30)
+	messageWarning := randStringBytes(30)
+	messageError := randStringBytes(30)
+
+	Trace(messageTrace)
+	Debug(messageDebug)
+	Info(messageInfo)
+	Warn(messageWarning)
+	Error(messageError, 42)
+
+	scanner := bufio.NewScanner(f)
+	count := 0
+	for scanner.Scan() {
+		split := strings.Split(scanner.Text(), " ")
+		switch count {
+		case 0:
+			require.Equal(t, "DEBUG", split[0])
+			require.Equal(t, messageDebug, split[3])
+		case 1:
+			require.Equal(t, "INFO", split[0])
+			require.Equal(t, messageInfo, split[3])
+		case 2:
+			require.Equal(t, "WARNING", split[0])
+			require.Equal(t, messageWarning, split[3])
+		case 3:
+			require.Equal(t, "ERROR", split[0])
+			require.Equal(t, messageError+"42", split[3])
+		}
+		require.Contains(t, split[4], "logger_test.go:")
+
+		count++
+	}
+	require.Equal(t, 4, count)
+
+	if err := scanner.Err(); err != nil {
+		t.Errorf("Bufio scanner error: %v", err)
+	}
+
+}
+
+func TestSetLevel(t *testing.T) {
+
+	f, err := ioutil.TempFile("", "_logger_set_output_*")
+	require.NoError(t, err)
+	defer func() {
+		os.Remove(f.Name())
+	}()
+
+	// Mock data
+	defer ResetForTest()
+	std.filePath = f.Name()
+	os.Unsetenv(envLogToConsole)
+
+	err = Reinit()
+	require.NoError(t, err)
+
+	err = SetLevel("unknown")
+	require.Error(t, err)
+	require.Equal(t, logrus.InfoLevel, GetLevel())
+
+	err = SetLevel("warning")
+	require.NoError(t, err)
+	require.Equal(t, logrus.WarnLevel, GetLevel())
+
+	messageInfo := randStringBytes(30)
+	messageWarning := randStringBytes(30)
+
+	Infof("%s", messageInfo)
+	Warnf("%s", messageWarning)
+
+	content, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.NotContains(t, string(content), messageInfo)
+	require.Contains(t, string(content), messageWarning)
+
+	SetLevelL(logrus.TraceLevel)
+	require.Equal(t, logrus.TraceLevel, GetLevel())
+}
+
+func TestSetLogFilePath(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_set_path_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+	os.Unsetenv(envLogToConsole)
+	defer ResetForTest()
+	std.filePath = filepath.Join(dir, "initial.log")
+
+	err = Reinit()
+	require.NoError(t, err)
+
+	// Parent directories should be created
+	path := filepath.Join(dir, "nested", "app.log")
+	err = SetLogFilePath(path)
+	require.NoError(t, err)
+
+	message := randStringBytes(30)
+	Errorf("%s", message)
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), message)
+
+	// Directories can't be used as log file
+	err = SetLogFilePath(dir)
+	require.Error(t, err)
+	require.Equal(t, path, std.filePath)
+}
+
+func TestSetRotationConfig(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_rotation_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+	os.Unsetenv(envLogToConsole)
+	defer ResetForTest()
+	std.filePath = filepath.Join(dir, "app.log")
+
+	err = Reinit()
+	require.NoError(t, err)
+
+	SetRotationConfig(RotationConfig{MaxSize: 1})
+
+	// Write a bit more than a megabyte to trigger a rotation
+	message := randStringBytes(1024)
+	for i := 0; i < 1100; i++ {
+		Infof("%s", message)
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+}
+
+func TestSetOutput(t *testing.T) {
+
+	f, err := ioutil.TempFile("", "_logger_set_output_*")
+	require.NoError(t, err)
+	defer func() {
+		os.Remove(f.Name())
+	}()
+
+	// Mock data
+	defer ResetForTest()
+	std.filePath = f.Name()
+	os.Unsetenv(envLogToConsole)
+
+	err = Reinit()
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	require.Equal(t, &buf, Writer())
+
+	messageBuffer := randStringBytes(30)
+	Errorf("%s", messageBuffer)
+
+	// Only the given writer receives the log
+	require.Contains(t, buf.String(), messageBuffer)
+	content, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Empty(t, content)
+
+	ResetOutput()
+
+	messageFile := randStringBytes(30)
+	Errorf("%s", messageFile)
+
+	require.NotContains(t, buf.String(), messageFile)
+	content, err = ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Contains(t, string(content), messageFile)
+}
+
+func TestResetOutputKeepsConsole(t *testing.T) {
+
+	os.Setenv(envLogToConsole, "true")
+	defer os.Unsetenv(envLogToConsole)
+
+	// The console setting given to New wins over the environment
+	l := New(WithConsole(false), WithFileLogging(false))
+	l.SetOutput(ioutil.Discard)
+	l.ResetOutput()
+	require.False(t, l.Config().Console)
+
+	os.Unsetenv(envLogToConsole)
+	l = New(WithConsole(true), WithFileLogging(false))
+	l.SetOutput(ioutil.Discard)
+	l.ResetOutput()
+	require.True(t, l.Config().Console)
+}
+
+func TestFlushAndClose(t *testing.T) {
+
+	f, err := ioutil.TempFile("", "_logger_set_output_*")
+	require.NoError(t, err)
+	defer func() {
+		os.Remove(f.Name())
+	}()
+
+	// Mock data
+	defer ResetForTest()
+	std.filePath = f.Name()
+	os.Unsetenv(envLogToConsole)
+
+	err = Reinit()
+	require.NoError(t, err)
+
+	// Buffered output is written on flush
+	var buf bytes.Buffer
+	SetOutput(bufio.NewWriter(&buf))
+
+	message := randStringBytes(30)
+	Errorf("%s", message)
+	require.Empty(t, buf.String())
+
+	err = Flush()
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), message)
+
+	ResetOutput()
+
+	// Logging after close reopens the file
+	messageBeforeClose := randStringBytes(30)
+	messageAfterClose := randStringBytes(30)
+
+	Errorf("%s", messageBeforeClose)
+	err = Close()
+	require.NoError(t, err)
+	Errorf("%s", messageAfterClose)
+
+	content, err := ioutil.ReadFile(f.Name())
+	require.NoError(t, err)
+	require.Contains(t, string(content), messageBeforeClose)
+	require.Contains(t, string(content), messageAfterClose)
+}
+
+func TestSetCallerSkip(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	wrapper := func(message string) {
+		l.Infof("%s", message)
+	}
+
+	// Without the skip the wrapper is reported
+	wrapper(randStringBytes(30))
+	_, _, wrapperLine, _ := runtime.Caller(0)
+	require.NotContains(t, buf.String(), fmt.Sprintf("logger_test.go:%d ", wrapperLine-1))
+
+	buf.Reset()
+	l.SetCallerSkip(1)
+	wrapper(randStringBytes(30))
+	_, _, callerLine, _ := runtime.Caller(0)
+	require.Contains(t, buf.String(), fmt.Sprintf("logger_test.go:%d ", callerLine-1))
+}
+
+func TestSetReportCaller(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetReportCaller(false)
+
+	message := randStringBytes(30)
+	l.Infof("%s", message)
+	require.NotContains(t, buf.String(), "file:")
+	require.NotContains(t, buf.String(), "func:")
+	require.Contains(t, buf.String(), message)
+
+	buf.Reset()
+	l.SetReportCaller(true)
+	l.Infof("%s", message)
+	require.Contains(t, buf.String(), "file:")
+}
+
+func TestSetCallerFormat(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	entry := &logrus.Entry{
+		Logger:  l.log,
+		Message: "message",
+		Level:   logrus.InfoLevel,
+		Data: logrus.Fields{
+			"file":     "github.com/acme/app/db/pool.go",
+			"line":     42,
+			"function": "github.com/acme/app/db.(*Pool).Get",
+		},
+	}
+
+	line, err := l.formatter.Format(entry)
+	require.NoError(t, err)
+	require.Contains(t, string(line), " file:github.com/acme/app/db/pool.go:42 func:github.com/acme/app/db.(*Pool).Get")
+
+	l.SetCallerFormat(CallerShort)
+	line, err = l.formatter.Format(entry)
+	require.NoError(t, err)
+	require.Contains(t, string(line), " file:pool.go:42 func:db.(*Pool).Get")
+
+	l.SetFormat(FormatJSON)
+	line, err = l.formatter.Format(entry)
+	require.NoError(t, err)
+	require.Contains(t, string(line), `"file":"pool.go","line":42,"function":"db.(*Pool).Get"`)
+
+	l.SetCallerFormat(CallerNone)
+	l.Infof("%s", "message")
+	require.NotContains(t, buf.String(), `"file"`)
+	require.NotContains(t, buf.String(), `"function"`)
+
+	buf.Reset()
+	l.SetFormat(FormatText)
+	l.Infof("%s", "message")
+	require.NotContains(t, buf.String(), "file:")
+	require.NotContains(t, buf.String(), "func:")
+}
+
+func TestSetCallerStyle(t *testing.T) {
+
+	l := New(WithConsole(false), WithFileLogging(false))
+	entry := &logrus.Entry{
+		Logger:  l.log,
+		Message: "message",
+		Level:   logrus.InfoLevel,
+		Data: logrus.Fields{
+			"file":     "handler/user.go",
+			"line":     42,
+			"function": "github.com/acme/app/handler.(*User).Get",
+		},
+	}
+
+	l.SetCallerStyle(CallerCombined)
+	line, err := l.formatter.Format(entry)
+	require.NoError(t, err)
+	require.Contains(t, string(line), " message caller:handler/user.go:42")
+	require.NotContains(t, string(line), "func:")
+
+	l.SetFormat(FormatJSON)
+	line, err = l.formatter.Format(entry)
+	require.NoError(t, err)
+	require.Contains(t, string(line), `"message":"message","caller":"handler/user.go:42"}`)
+
+	l.SetCallerFormat(CallerShort)
+	line, err = l.formatter.Format(entry)
+	require.NoError(t, err)
+	require.Contains(t, string(line), `"caller":"user.go:42"`)
+
+	l.SetCallerFormat(CallerNone)
+	line, err = l.formatter.Format(entry)
+	require.NoError(t, err)
+	require.NotContains(t, string(line), "caller")
+
+	l.SetCallerFormat(CallerFull)
+	l.SetCallerStyle(CallerSeparate)
+	line, err = l.formatter.Format(entry)
+	require.NoError(t, err)
+	require.Contains(t, string(line), `"file":"handler/user.go","line":42,"function":"github.com/acme/app/handler.(*User).Get"`)
+
+	// A custom caller field can't clobber the combined caller
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+	l.SetCallerStyle(CallerCombined)
+	l.WithFields(map[string]interface{}{"caller": "spoofed.go:1"}).Infof("message")
+	require.Contains(t, buf.String(), `"fields.caller":"spoofed.go:1"`)
+	require.Contains(t, buf.String(), `"caller":"logger_test.go:`)
+	require.Equal(t, 1, strings.Count(buf.String(), `"caller":`))
+}
+
+func TestSetTrimPrefixes(t *testing.T) {
+
+	file := "/home/ci/build/app/handler/user.go"
+	function := "github.com/acme/app/handler.(*User).Get"
+
+	// Without a matching prefix the import path of the package replaces the directory
+	require.Equal(t, "github.com/acme/app/handler/user.go", callerPath(file, function, nil))
+	require.Equal(t, "handler/user.go", callerPath(file, function, []string{"/home/ci/build/app/"}))
+	require.Equal(t, "handler/user.go", callerPath(file, function, []string{"/src", "/home/ci/build/app"}))
+	require.Equal(t, "/home/ci/build/app/main.go", callerPath("/home/ci/build/app/main.go", "main.main", nil))
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	// Caller of the main module is relative to the module root
+	l.Infof("%s", randStringBytes(30))
+	_, _, line, _ := runtime.Caller(0)
+	require.Contains(t, buf.String(), fmt.Sprintf(" file:logger_test.go:%d ", line-1))
+
+	_, dir, _, _ := runtime.Caller(0)
+	buf.Reset()
+	l.SetTrimPrefixes(filepath.Dir(filepath.Dir(dir)))
+	l.Infof("%s", randStringBytes(30))
+	_, _, line, _ = runtime.Caller(0)
+	require.Contains(t, buf.String(), fmt.Sprintf(" file:%s/logger_test.go:%d ", filepath.Base(filepath.Dir(dir)), line-1))
+}
+
+func TestSetSequenceField(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.Infof("%s", "not numbered")
+	require.NotContains(t, buf.String(), "seq=")
+
+	buf.Reset()
+	l.SetSequenceField("seq")
+	l.Infof("%s", "first")
+	l.WithFields(map[string]interface{}{"user": "alice"}).Info("second")
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.True(t, strings.HasSuffix(lines[0], " seq=1"))
+	require.True(t, strings.HasSuffix(lines[1], " seq=2 user=alice"))
+
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.Infof("%s", "third")
+	require.Contains(t, buf.String(), `"seq":3`)
+
+	// Concurrent lines get unique numbers and are written in their order
+	var out syncBuffer
+	l.SetOutput(&out)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Infof("%d", j)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var fields struct {
+		Seq uint64 `json:"seq"`
+	}
+	lines = strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 800)
+	for i, line := range lines {
+		require.NoError(t, json.Unmarshal([]byte(line), &fields))
+		require.Equal(t, uint64(i+4), fields.Seq)
+	}
+
+	// Reserved names can't clobber the fields of the logger
+	buf.Reset()
+	l.SetOutput(&buf)
+	l.SetSequenceField("time")
+	l.Infof("%s", "reserved")
+	require.Contains(t, buf.String(), `"fields.time":804`)
+	require.Equal(t, 1, strings.Count(buf.String(), `"time":`))
+}
+
+func TestSetIncludeHostPID(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.Infof("%s", "message")
+	require.NotContains(t, buf.String(), "pid=")
+
+	host, err := os.Hostname()
+	require.NoError(t, err)
+
+	buf.Reset()
+	l.SetIncludeHostPID(true)
+	l.Infof("%s", "message")
+	require.Contains(t, buf.String(), fmt.Sprintf(" host=%s pid=%d", host, os.Getpid()))
+
+	// Custom fields take precedence
+	buf.Reset()
+	l.WithFields(map[string]interface{}{"host": "example.com"}).Info("message")
+	require.Contains(t, buf.String(), " host=example.com pid=")
+
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	l.Infof("%s", "message")
+	require.Contains(t, buf.String(), fmt.Sprintf(`"host":"%s"`, host))
+	require.Contains(t, buf.String(), fmt.Sprintf(`"pid":%d`, os.Getpid()))
+
+	buf.Reset()
+	l.SetIncludeHostPID(false)
+	l.Infof("%s", "message")
+	require.NotContains(t, buf.String(), "pid")
+}
+
+func TestLevelString(t *testing.T) {
+
+	l := New(WithConsole(false), WithFileLogging(false))
+	require.Equal(t, "info", l.LevelString())
+	require.False(t, l.IsDebugEnabled())
+	require.False(t, l.IsTraceEnabled())
+
+	require.NoError(t, l.SetLevel(l.LevelString()))
+	require.Equal(t, logrus.InfoLevel, l.GetLevel())
+
+	l.SetDebugLogging(true)
+	require.Equal(t, "debug", l.LevelString())
+	require.True(t, l.IsDebugEnabled())
+	require.False(t, l.IsTraceEnabled())
+
+	l.SetLevelL(logrus.TraceLevel)
+	require.True(t, l.IsDebugEnabled())
+	require.True(t, l.IsTraceEnabled())
+
+	l.SetLevelL(logrus.WarnLevel)
+	require.Equal(t, "warning", l.LevelString())
+}
+
+func TestLogFileName(t *testing.T) {
+
+	tests := []struct {
+		executable string
+		expected   string
+	}{
+		{"app", "app.log"},
+		{"app.exe", "app.log"},
+		{"my.service", "my.log"},
+		{"/usr/local/bin/my.app.exe", "my.app.log"},
+		{"app.exe.exe", "app.exe.log"},
+		{"", "app.log"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.executable, func(t *testing.T) {
+			require.Equal(t, tt.expected, logFileName(tt.executable, ".log"))
+		})
+	}
+
+	// Empty os.Args
+	old := os.Args
+	defer func() {
+		os.Args = old
+	}()
+	os.Args = nil
+	require.Equal(t, "app.log", getLogFileName(".log"))
+}
+
+func TestInit(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_init_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+	os.Unsetenv(envLogToConsole)
+
+	// Log file can't be created inside a regular file
+	f, err := ioutil.TempFile(dir, "_logger_init_*")
+	require.NoError(t, err)
+	defer ResetForTest()
+	std.filePath = filepath.Join(f.Name(), "app.log")
+
+	err = Reinit()
+	require.Error(t, err)
+
+	std.filePath = filepath.Join(dir, "app.log")
+	err = Reinit()
+	require.NoError(t, err)
+
+	// Init only initializes once
+	err = Init()
+	first := std.rotatedFile
+	require.NoError(t, err)
+	err = Init()
+	require.NoError(t, err)
+	require.Same(t, first, std.rotatedFile)
+}
+
+func TestInitKeepsLevel(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_init_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+	os.Unsetenv(envLogToConsole)
+
+	ResetForTest()
+	defer ResetForTest()
+	require.NoError(t, SetLogFilePath(filepath.Join(dir, "app.log")))
+
+	// A level set before Init survives it
+	SetDebugLogging(true)
+	require.NoError(t, Init())
+	require.Equal(t, logrus.DebugLevel, GetLevel())
+
+	// Reinit starts over with the default level
+	require.NoError(t, Reinit())
+	require.Equal(t, logrus.InfoLevel, GetLevel())
+
+	// Without a level set Init uses Info
+	ResetForTest()
+	require.NoError(t, SetLogFilePath(filepath.Join(dir, "app.log")))
+	require.NoError(t, Init())
+	require.Equal(t, logrus.InfoLevel, GetLevel())
+}
+
+func TestInitKeepsFormatter(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_init_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+	os.Unsetenv(envLogToConsole)
+	defer os.Unsetenv(envLogFormat)
+
+	ResetForTest()
+	defer ResetForTest()
+	filename := filepath.Join(dir, "app.log")
+	require.NoError(t, SetLogFilePath(filename))
+
+	// The format loaded from the environment and the redacted keys survive Init
+	os.Setenv(envLogFormat, "json")
+	require.NoError(t, LoadFromEnv())
+	SetRedactedKeys("password")
+	require.NoError(t, Init())
+	WithFields(map[string]interface{}{"password": "hunter2"}).Infof("login")
+	require.NoError(t, Flush())
+
+	content, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"message":"login"`)
+	require.Contains(t, string(content), `"password":"***"`)
+
+	// Reinit starts over with the default formatter
+	require.NoError(t, Reinit())
+	WithFields(map[string]interface{}{"password": "hunter2"}).Infof("relogin")
+	require.NoError(t, Flush())
+
+	content, err = ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "INFO ")
+	require.Contains(t, string(content), "password=hunter2")
+}
+
+func TestResetForTest(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_reset_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+	os.Unsetenv(envLogToConsole)
+
+	require.NoError(t, SetLogFilePath(filepath.Join(dir, "app.log")))
+	SetLevelL(logrus.TraceLevel)
+	SetPrefix("test: ")
+	SetFormat(FormatJSON)
+	SetReportCaller(false)
+	SetSampling(1, time.Hour)
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	std.log.ExitFunc = func(int) {}
+	LogCounts()
+
+	ResetForTest()
+
+	require.Equal(t, logrus.InfoLevel, GetLevel())
+	require.Equal(t, getLogFileName(".log"), std.filePath)
+	require.Nil(t, std.output)
+	require.Equal(t, defaultRotationConfig(), std.rotation)
+	require.Empty(t, std.formatter.prefix)
+	require.Equal(t, FormatText, std.formatter.format)
+	require.Zero(t, std.noCaller)
+	require.Nil(t, std.sampler.Load().(*sampler))
+	require.NotNil(t, std.log.ExitFunc)
+	for _, hooks := range std.log.Hooks {
+		require.Equal(t, []logrus.Hook{std.consoleHook}, hooks)
+	}
+}
+
 func TestWrite(t *testing.T) {
 	w := Writer()
 	require.NotNil(t, w)
 }
 
+func BenchmarkInfofDisabled(b *testing.B) {
+	l := New(WithConsole(false), WithLevel(logrus.WarnLevel))
+	l.SetOutput(ioutil.Discard)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Infof("%s", "disabled")
+	}
+}
+
+// BenchmarkInfofDisabledUnguarded measures the former path resolving the caller before the level check
+func BenchmarkInfofDisabledUnguarded(b *testing.B) {
+	l := New(WithConsole(false), WithLevel(logrus.WarnLevel))
+	l.SetOutput(ioutil.Discard)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.newEntry(0, logrus.InfoLevel, nil, true).Logf(logrus.InfoLevel, "%s", "disabled")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use, for outputs written from other goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func (b *syncBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
 func convertLevel(level logrus.Level) string {
 	levelMap := map[logrus.Level]string{
 		logrus.PanicLevel: "PANIC",
 		logrus.FatalLevel: "FATAL",
 		logrus.ErrorLevel: "ERROR",
-		logrus.WarnLevel:  "WARN",
+		logrus.WarnLevel:  "WARNING",
 		logrus.InfoLevel:  "INFO",
 		logrus.DebugLevel: "DEBUG",
 		logrus.TraceLevel: "TRACE",
@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	lokiPushPath      = "/loki/api/v1/push"
+	lokiQueueSize     = 4096
+	lokiBatchSize     = 512
+	lokiBatchInterval = time.Second
+)
+
+// lokiEntry is a log line queued to be pushed to Loki.
+type lokiEntry struct {
+	time     time.Time
+	line     string
+	metadata map[string]string
+}
+
+// lokiHook pushes entries to Loki in batches. Entries are queued and pushed by a single goroutine when a batch is full,
+// the batch interval elapses or on flush, so that logging never blocks on the network. Entries are dropped if the
+// queue is full or all attempts fail.
+type lokiHook struct {
+	logger *Logger
+	labels map[string]string
+	// sender posts the batches with the retry of the HTTP hook
+	sender    *httpHook
+	queue     chan lokiEntry
+	flushes   chan chan struct{}
+	batchSize int
+	interval  time.Duration
+	dropped   uint64
+}
+
+// AddLokiHook pushes log lines to the Loki instance at endpoint, e.g. http://loki:3100, under a stream with labels.
+// The message is pushed as the log line and the level, caller and custom fields as structured metadata, which
+// requires Loki 2.9 or later. Lines are pushed in the background in batches of up to 512 lines at least every second,
+// retrying server errors. Lines are dropped if the queue overflows or they can't be delivered. Flush and Close push the
+// pending lines. opts configure the requests like for AddHTTPHook, e.g. the X-Scope-OrgID header. It returns an error if
+// endpoint is not a valid http(s) URL.
+func (l *Logger) AddLokiHook(endpoint string, labels map[string]string, opts ...HTTPHookOption) error {
+	hook, err := newLokiHook(l, endpoint, labels, opts...)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.lokiHooks = append(l.lokiHooks, hook)
+	l.mu.Unlock()
+
+	l.AddHook(hook)
+	go hook.run()
+
+	return nil
+}
+
+// newLokiHook creates a hook pushing entries of l to the push API of the Loki instance at endpoint.
+func newLokiHook(l *Logger, endpoint string, labels map[string]string, opts ...HTTPHookOption) (*lokiHook, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(u.Path, lokiPushPath) {
+		u.Path = strings.TrimSuffix(u.Path, "/") + lokiPushPath
+	}
+
+	sender, err := newHTTPHook(u.String(), logrus.TraceLevel, l, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Loki requires at least one label
+	streamLabels := map[string]string{"job": getLogFileName("")}
+	if len(labels) > 0 {
+		streamLabels = make(map[string]string, len(labels))
+		for key, value := range labels {
+			streamLabels[key] = value
+		}
+	}
+
+	return &lokiHook{
+		logger:    l,
+		labels:    streamLabels,
+		sender:    sender,
+		queue:     make(chan lokiEntry, lokiQueueSize),
+		flushes:   make(chan chan struct{}),
+		batchSize: lokiBatchSize,
+		interval:  lokiBatchInterval,
+	}, nil
+}
+
+// Levels returns the levels the hook fires for.
+func (h *lokiHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire queues entry to be pushed, dropping it if the queue is full.
+func (h *lokiHook) Fire(entry *logrus.Entry) error {
+	e := lokiEntry{time: entry.Time, metadata: make(map[string]string, len(entry.Data)+1)}
+
+	f := h.logger.formatter
+	f.mu.RLock()
+	e.line = f.prefix + f.message(entry)
+	e.metadata["level"] = f.levelName(entry.Level)
+	for key, value := range entry.Data {
+		if !isCallerKey(key) {
+			value = f.fieldValue(key, value)
+		}
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		e.metadata[key] = fmt.Sprint(value)
+	}
+	f.mu.RUnlock()
+
+	select {
+	case h.queue <- e:
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+	}
+
+	return nil
+}
+
+// flush waits until the entries queued so far are pushed.
+func (h *lokiHook) flush() {
+	done := make(chan struct{})
+	h.flushes <- done
+	<-done
+}
+
+// run batches the queued entries and pushes the batches.
+func (h *lokiHook) run() {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	batch := make([]lokiEntry, 0, h.batchSize)
+	add := func(e lokiEntry) {
+		batch = append(batch, e)
+		if len(batch) >= h.batchSize {
+			h.push(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case e := <-h.queue:
+			add(e)
+		case <-ticker.C:
+			if len(batch) > 0 {
+				h.push(batch)
+				batch = batch[:0]
+			}
+		case done := <-h.flushes:
+			for queued := true; queued; {
+				select {
+				case e := <-h.queue:
+					add(e)
+				default:
+					queued = false
+				}
+			}
+			if len(batch) > 0 {
+				h.push(batch)
+				batch = batch[:0]
+			}
+			close(done)
+		}
+	}
+}
+
+// push posts batch as a single stream, counting its entries as dropped if it can't be delivered.
+func (h *lokiHook) push(batch []lokiEntry) {
+	values := make([][]interface{}, 0, len(batch))
+	for _, e := range batch {
+		values = append(values, []interface{}{strconv.FormatInt(e.time.UnixNano(), 10), e.line, e.metadata})
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"streams": []interface{}{
+			map[string]interface{}{"stream": h.labels, "values": values},
+		},
+	})
+	if err == nil {
+		err = h.sender.post(payload)
+	}
+	if err != nil {
+		atomic.AddUint64(&h.dropped, uint64(len(batch)))
+	}
+}
+
+// flushLoki pushes the pending entries of the Loki hooks, callers must hold l.mu.
+func (l *Logger) flushLoki() {
+	for _, hook := range l.lokiHooks {
+		hook.flush()
+	}
+}
+
+// AddLokiHook pushes log lines of the default logger to Loki. See Logger.AddLokiHook.
+func AddLokiHook(endpoint string, labels map[string]string, opts ...HTTPHookOption) error {
+	return std.AddLokiHook(endpoint, labels, opts...)
+}
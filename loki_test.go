@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+type lokiPush struct {
+	Streams []struct {
+		Stream map[string]string `json:"stream"`
+		Values [][]interface{}   `json:"values"`
+	} `json:"streams"`
+}
+
+func TestAddLokiHook(t *testing.T) {
+
+	var (
+		mu      sync.Mutex
+		pushes  []lokiPush
+		paths   []string
+		tenants []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var push lokiPush
+		require.NoError(t, json.Unmarshal(body, &push))
+
+		mu.Lock()
+		pushes = append(pushes, push)
+		paths = append(paths, r.URL.Path)
+		tenants = append(tenants, r.Header.Get("X-Scope-OrgID"))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+	l.SetRedactedKeys("token")
+
+	err := l.AddLokiHook(server.URL, map[string]string{"service": "api"}, WithHTTPHeader("X-Scope-OrgID", "tenant"))
+	require.NoError(t, err)
+
+	l.WithFields(map[string]interface{}{"request_id": 42, "token": "secret"}).
+		WithError(errors.New("timeout")).Errorf("%s", "request failed")
+	l.Infof("%s", "request served")
+
+	// Close pushes the pending batch
+	require.NoError(t, l.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, pushes, 1)
+	require.Equal(t, "/loki/api/v1/push", paths[0])
+	require.Equal(t, "tenant", tenants[0])
+	require.Len(t, pushes[0].Streams, 1)
+	stream := pushes[0].Streams[0]
+	require.Equal(t, map[string]string{"service": "api"}, stream.Stream)
+	require.Len(t, stream.Values, 2)
+
+	require.IsType(t, "", stream.Values[0][0])
+	require.Equal(t, "request failed", stream.Values[0][1])
+	metadata := stream.Values[0][2].(map[string]interface{})
+	require.Equal(t, "ERROR", metadata["level"])
+	require.Equal(t, "42", metadata["request_id"])
+	require.Equal(t, "***", metadata["token"])
+	require.Equal(t, "timeout", metadata["error"])
+	require.Contains(t, metadata["file"], "loki_test.go")
+	require.Equal(t, "request served", stream.Values[1][1])
+}
+
+func TestLokiHookBatches(t *testing.T) {
+
+	var (
+		mu       sync.Mutex
+		batches  []int
+		failures int32 = 1
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first request fails with a server error and is retried
+		if atomic.AddInt32(&failures, -1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var push lokiPush
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&push))
+		mu.Lock()
+		batches = append(batches, len(push.Streams[0].Values))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+	hook, err := newLokiHook(l, server.URL+"/loki/api/v1/push", nil)
+	require.NoError(t, err)
+	require.Equal(t, server.URL+"/loki/api/v1/push", hook.sender.url)
+	require.Contains(t, hook.labels, "job")
+	hook.batchSize = 3
+	hook.interval = 50 * time.Millisecond
+	l.log.AddHook(hook)
+	go hook.run()
+
+	// A full batch is pushed right away, the rest when the interval elapses
+	for i := 0; i < 4; i++ {
+		l.Infof("message %d", i)
+	}
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(batches) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []int{3, 1}, batches)
+	require.Zero(t, atomic.LoadUint64(&hook.dropped))
+}
+
+func TestLokiHookDropped(t *testing.T) {
+
+	l := New(WithConsole(false))
+	hook, err := newLokiHook(l, "http://localhost:3100", map[string]string{"service": "api"})
+	require.NoError(t, err)
+	hook.queue = make(chan lokiEntry, 1)
+
+	entry := logrus.NewEntry(l.log)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, hook.Fire(entry))
+	}
+	require.Equal(t, uint64(2), atomic.LoadUint64(&hook.dropped))
+
+	_, err = newLokiHook(l, "ftp://localhost:3100", nil)
+	require.Error(t, err)
+
+	// Without os.Args the job is named after the default app name
+	defer func(args []string) {
+		os.Args = args
+	}(os.Args)
+	os.Args = nil
+	hook, err = newLokiHook(l, "http://localhost:3100", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"job": defaultAppName}, hook.labels)
+}
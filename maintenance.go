@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"time"
+)
+
+// SetMaintenanceInterval compresses and prunes the rotated log files every d, disabled by default. Rotated files are
+// otherwise only compressed and removed by MaxBackups and MaxAge when the log file rotates, so on a low traffic service
+// backups left uncompressed, e.g. by a crash, and backups aging beyond MaxAge stay on disk until the next rotation. The
+// active file is never compressed. Files rotated daily are pruned by MaxAge the same way. A zero or negative d stops
+// the maintenance, which Close does too.
+func (l *Logger) SetMaintenanceInterval(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.stopMaintenance()
+	if d <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	l.maintenanceInterval = d
+	l.maintenanceStop = stop
+	go l.runMaintenance(d, stop)
+}
+
+// stopMaintenance stops the maintenance started by SetMaintenanceInterval without waiting for a run in progress,
+// callers must hold l.mu.
+func (l *Logger) stopMaintenance() {
+	if l.maintenanceStop != nil {
+		close(l.maintenanceStop)
+		l.maintenanceStop = nil
+	}
+	l.maintenanceInterval = 0
+}
+
+// runMaintenance maintains the rotated files every d until stop is closed.
+func (l *Logger) runMaintenance(d time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			l.maintain()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// maintain compresses and prunes the rotated files of the current log file in the background.
+func (l *Logger) maintain() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.dailyFile != nil {
+		l.dailyFile.prune()
+		return
+	}
+	if l.rotatedFile == nil {
+		return
+	}
+
+	// lumberjack compresses and prunes the backups in the background whenever it opens the file, the reopening write
+	// of nothing leaves the file as is
+	_ = l.rotatedFile.Close()
+	_, _ = l.rotatedFile.Write(nil)
+	if l.backups != nil {
+		l.backups.notify()
+	}
+}
+
+// prune removes the files beyond maxAge days.
+func (d *dailyFile) prune() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// Errors can't be reported from here, the files are retried on the next run
+	_ = d.removeExpired(d.now())
+}
+
+// SetMaintenanceInterval compresses and prunes the rotated files of the default logger every d. See
+// Logger.SetMaintenanceInterval.
+func SetMaintenanceInterval(d time.Duration) {
+	std.SetMaintenanceInterval(d)
+}
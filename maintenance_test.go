@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetMaintenanceInterval(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_maintenance_*")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	filename := filepath.Join(dir, "app.log")
+	l := New(WithConsole(false), WithFilePath(filename),
+		WithRotationConfig(RotationConfig{MaxSize: 10, MaxBackups: 2, Compress: true}))
+	defer l.Close()
+	l.Infof("opened")
+
+	// Backups appearing after the last rotation, e.g. left by a crashed process
+	now := time.Now().UTC()
+	for _, age := range []time.Duration{time.Hour, 2 * time.Hour, 3 * time.Hour} {
+		name := "app-" + now.Add(-age).Format(backupTimeFormat) + ".log"
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(name), 0644))
+	}
+
+	l.SetMaintenanceInterval(10 * time.Millisecond)
+	require.Equal(t, 10*time.Millisecond, l.Config().MaintenanceInterval)
+
+	require.Eventually(t, func() bool {
+		compressed, _ := filepath.Glob(filepath.Join(dir, "app-*.log.gz"))
+		plain, _ := filepath.Glob(filepath.Join(dir, "app-*.log"))
+		return len(compressed) == 2 && len(plain) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The active file is left alone
+	content, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "opened")
+
+	require.NoError(t, l.Close())
+	require.Zero(t, l.Config().MaintenanceInterval)
+}
+
+func TestMaintenanceDaily(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_maintenance_*")
+	require.NoError(t, err)
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	filename := filepath.Join(dir, "app.log")
+	l := New(WithConsole(false), WithFilePath(filename), WithRotationConfig(RotationConfig{MaxAge: 1}))
+	require.NoError(t, l.RotateDaily(true))
+	defer l.Close()
+
+	expired := dailyFileName(filename, time.Now().AddDate(0, 0, -3))
+	require.NoError(t, ioutil.WriteFile(expired, []byte("old"), 0644))
+
+	l.SetMaintenanceInterval(10 * time.Millisecond)
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(expired)
+		return os.IsNotExist(err)
+	}, 5*time.Second, 10*time.Millisecond)
+}
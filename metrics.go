@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// levelCounter counts the entries logged per level.
+type levelCounter struct {
+	// counts are indexed by level and accessed atomically
+	counts [logrus.TraceLevel + 1]uint64
+}
+
+// Levels returns the levels the hook fires for.
+func (c *levelCounter) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire counts entry.
+func (c *levelCounter) Fire(entry *logrus.Entry) error {
+	if int(entry.Level) < len(c.counts) {
+		atomic.AddUint64(&c.counts[entry.Level], 1)
+	}
+
+	return nil
+}
+
+// snapshot returns the counts by level.
+func (c *levelCounter) snapshot() map[logrus.Level]uint64 {
+	counts := make(map[logrus.Level]uint64, len(c.counts))
+	for _, level := range logrus.AllLevels {
+		counts[level] = atomic.LoadUint64(&c.counts[level])
+	}
+
+	return counts
+}
+
+// levelCounter returns the counter of the logged entries, adding it on first use so that loggers not reporting
+// metrics don't pay for counting.
+func (l *Logger) levelCounter() *levelCounter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.counter == nil {
+		l.counter = &levelCounter{}
+		l.AddHook(l.counter)
+	}
+
+	return l.counter
+}
+
+// LogCounts returns the number of lines logged per level since the first call of LogCounts or MetricsCollector.
+// Counting starts on the first call, which returns zero counts.
+func (l *Logger) LogCounts() map[logrus.Level]uint64 {
+	return l.levelCounter().snapshot()
+}
+
+// LogCounts returns the number of lines logged per level by the default logger. See Logger.LogCounts.
+func LogCounts() map[logrus.Level]uint64 {
+	return std.LogCounts()
+}
@@ -0,0 +1,43 @@
+//go:build prometheus
+// +build prometheus
+
+package logger
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// metricsCollector exports the counts of a levelCounter as log_messages_total.
+type metricsCollector struct {
+	counter *levelCounter
+	desc    *prometheus.Desc
+}
+
+// Describe sends the descriptor of log_messages_total.
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect sends the count of every level.
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	for level, count := range c.counter.snapshot() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(count), level.String())
+	}
+}
+
+// MetricsCollector returns a collector exporting the number of lines logged per level as
+// log_messages_total{level="error"}, to be registered with a Prometheus registry. Counting starts on the first call.
+// It's only available when built with the prometheus build tag.
+func (l *Logger) MetricsCollector() prometheus.Collector {
+	return &metricsCollector{
+		counter: l.levelCounter(),
+		desc: prometheus.NewDesc("log_messages_total", "Number of log messages by level.", []string{"level"},
+			nil),
+	}
+}
+
+// MetricsCollector returns a collector exporting the number of lines logged per level by the default logger. See
+// Logger.MetricsCollector.
+func MetricsCollector() prometheus.Collector {
+	return std.MetricsCollector()
+}
@@ -0,0 +1,42 @@
+//go:build prometheus
+// +build prometheus
+
+package logger
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsCollector(t *testing.T) {
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+
+	collector := l.MetricsCollector()
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+
+	l.Errorf("%s", "first")
+	l.Errorf("%s", "second")
+	l.Infof("%s", "info")
+
+	expected := `
+# HELP log_messages_total Number of log messages by level.
+# TYPE log_messages_total counter
+log_messages_total{level="debug"} 0
+log_messages_total{level="error"} 2
+log_messages_total{level="fatal"} 0
+log_messages_total{level="info"} 1
+log_messages_total{level="panic"} 0
+log_messages_total{level="trace"} 0
+log_messages_total{level="warning"} 0
+`
+	err := testutil.GatherAndCompare(registry, strings.NewReader(expected), "log_messages_total")
+	require.NoError(t, err)
+}
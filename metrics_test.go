@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogCounts(t *testing.T) {
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+
+	require.Zero(t, l.LogCounts()[logrus.ErrorLevel])
+
+	l.Errorf("%s", "first")
+	l.Errorf("%s", "second")
+	l.Warnf("%s", "warning")
+	l.Debugf("%s", "not logged")
+
+	counts := l.LogCounts()
+	require.Equal(t, uint64(2), counts[logrus.ErrorLevel])
+	require.Equal(t, uint64(1), counts[logrus.WarnLevel])
+	require.Zero(t, counts[logrus.DebugLevel])
+
+	// The hook is added once
+	require.Len(t, l.log.Hooks[logrus.ErrorLevel], 2)
+}
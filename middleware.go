@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// requestIDHeader carries the request ID propagated by clients and returned to them.
+const requestIDHeader = "X-Request-ID"
+
+// requestIDKey is the context key of the request ID set by HTTPMiddleware.
+type requestIDKey struct{}
+
+// responseRecorder captures the status code and the number of bytes written to a http.ResponseWriter.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+// WriteHeader records status and writes it.
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the number of bytes written, the status defaults to 200 OK.
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += n
+
+	return n, err
+}
+
+// Flush flushes the underlying writer if it supports flushing, e.g. for server-sent events.
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// HTTPMiddleware returns a handler logging every request served by next with its method, path, status code, bytes
+// written, latency in milliseconds in the numeric http.latency_ms field and request ID. The request ID is taken from
+// the X-Request-ID header or generated, returned in the response header and available to next with
+// RequestIDFromContext. 5xx responses are logged at level Error, 4xx at level Warn and the others at level Info.
+func (l *Logger) HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+		r = r.WithContext(context.WithValue(r.Context(), requestIDKey{}, requestID))
+
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+
+		l.logRequest(r, rec, requestID, start)
+	})
+}
+
+// logRequest logs a served request. The caller fields are omitted since they'd point at the middleware.
+func (l *Logger) logRequest(r *http.Request, rec *responseRecorder, requestID string, start time.Time) {
+	level := logrus.InfoLevel
+	switch {
+	case rec.status >= http.StatusInternalServerError:
+		level = logrus.ErrorLevel
+	case rec.status >= http.StatusBadRequest:
+		level = logrus.WarnLevel
+	}
+	if !l.isLevelEnabled(level) {
+		return
+	}
+
+	fields := copyFields(l.contextFields(r.Context()), map[string]interface{}{
+		"http.method":     r.Method,
+		"http.path":       r.URL.Path,
+		"http.status":     rec.status,
+		"http.bytes":      rec.bytes,
+		"http.latency_ms": float64(time.Since(start)) / float64(time.Millisecond),
+		"request_id":      requestID,
+		"peer":            r.RemoteAddr,
+	})
+
+	if entry := l.newEntry(0, level, fields, false); entry != nil {
+		l.write(entry, level, "finished request")
+	}
+}
+
+// newRequestID returns a random 16 bytes hex encoded request ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+// RequestIDFromContext returns the request ID set by HTTPMiddleware, an empty string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// HTTPMiddleware returns a handler logging every request served by next with the default logger. See
+// Logger.HTTPMiddleware.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return std.HTTPMiddleware(next)
+}
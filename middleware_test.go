@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	var requestID string
+	handler := l.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID = RequestIDFromContext(r.Context())
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			_, _ = w.Write([]byte("hello"))
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users?id=1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, requestID, 32)
+	require.Equal(t, requestID, rec.Header().Get("X-Request-ID"))
+
+	line := buf.String()
+	require.True(t, strings.HasPrefix(line, "INFO "))
+	require.Contains(t, line, " finished request ")
+	require.Contains(t, line, " http.bytes=5 ")
+	require.Contains(t, line, " http.latency_ms=")
+	require.Contains(t, line, " http.method=GET ")
+	require.Contains(t, line, " http.path=/users ")
+	require.Contains(t, line, " http.status=200 ")
+	require.Contains(t, line, " request_id="+requestID)
+
+	// The request ID is propagated and the level follows the status
+	buf.Reset()
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.Header.Set("X-Request-ID", "4bf92f3577b34da6")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	require.Equal(t, "4bf92f3577b34da6", requestID)
+	require.True(t, strings.HasPrefix(buf.String(), "WARNING "))
+	require.Contains(t, buf.String(), " http.status=404 ")
+	require.Contains(t, buf.String(), " request_id=4bf92f3577b34da6")
+
+	buf.Reset()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/broken", nil))
+	require.True(t, strings.HasPrefix(buf.String(), "ERROR "))
+	require.Contains(t, buf.String(), " http.status=500 ")
+	require.Contains(t, buf.String(), " http.bytes=0 ")
+
+	// The latency is numeric and context fields named like a field of the logger are renamed
+	buf.Reset()
+	l.SetFormat(FormatJSON)
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{"level": "spoofed"})
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &fields))
+	require.IsType(t, float64(0), fields["http.latency_ms"])
+	require.Equal(t, "INFO", fields["level"])
+	require.Equal(t, "spoofed", fields["fields.level"])
+}
@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// LogFunc is called by the callbacks registered with OnLog with the level, message and fields of each logged entry.
+type LogFunc func(level logrus.Level, msg string, fields map[string]interface{})
+
+// OnLog registers fn to be called for every entry that is logged, e.g. to show the lines in a UI or to check them in a
+// test harness without parsing the output. Callbacks run in registration order on the logging goroutine after the
+// filters, right before the entry is written, and each get their own copy of its fields they may modify and keep. A
+// slow callback blocks the logging call, so heavy work should be handed off to another goroutine. Callbacks must not
+// log through the logger.
+func (l *Logger) OnLog(fn LogFunc) {
+	l.onLogMu.Lock()
+	defer l.onLogMu.Unlock()
+
+	callbacks, _ := l.onLog.Load().([]LogFunc)
+	// Copied so that entries being logged keep a consistent view
+	callbacks = append(callbacks[:len(callbacks):len(callbacks)], fn)
+	l.onLog.Store(callbacks)
+}
+
+// notify calls the callbacks registered with OnLog for entry with level and msg.
+func (l *Logger) notify(entry *logrus.Entry, level logrus.Level, msg string) {
+	callbacks, _ := l.onLog.Load().([]LogFunc)
+	if len(callbacks) == 0 {
+		return
+	}
+
+	for _, fn := range callbacks {
+		fn(level, msg, copyFields(entry.Data, nil))
+	}
+}
+
+// OnLog registers fn to be called for every entry logged by the default logger. See Logger.OnLog.
+func OnLog(fn LogFunc) {
+	std.OnLog(fn)
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnLog(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	type call struct {
+		level  logrus.Level
+		msg    string
+		fields map[string]interface{}
+	}
+	var calls []call
+	var order []int
+	l.OnLog(func(level logrus.Level, msg string, fields map[string]interface{}) {
+		calls = append(calls, call{level, msg, fields})
+		order = append(order, 1)
+		// Modifying the fields doesn't affect the next callback
+		delete(fields, "user")
+	})
+	var seen []interface{}
+	l.OnLog(func(_ logrus.Level, _ string, fields map[string]interface{}) {
+		seen = append(seen, fields["user"])
+		order = append(order, 2)
+	})
+	l.AddFilter(func(entry *logrus.Entry) bool {
+		return entry.Message != "filtered"
+	})
+
+	l.WithFields(map[string]interface{}{"user": "alice"}).Warnf("login %d", 1)
+	l.Debugf("below level")
+	l.Infof("filtered")
+
+	require.Len(t, calls, 1)
+	require.Equal(t, logrus.WarnLevel, calls[0].level)
+	require.Equal(t, "login 1", calls[0].msg)
+	require.NotContains(t, calls[0].fields, "user")
+	require.Equal(t, []interface{}{"alice"}, seen)
+	require.Equal(t, []int{1, 2}, order)
+	require.Contains(t, buf.String(), "login 1")
+	require.Contains(t, buf.String(), "user=alice")
+
+	// The fields are a copy the callback may keep and modify
+	calls[0].fields["user"] = "bob"
+	l.Infof("next")
+	require.Len(t, calls, 2)
+	require.NotContains(t, calls[1].fields, "user")
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// Option configures a Logger created by New.
+type Option func(*Logger)
+
+// WithFilePath sets the path of the rotated log file.
+func WithFilePath(path string) Option {
+	return func(l *Logger) {
+		l.filePath = path
+	}
+}
+
+// WithFileLogging enables or disables writing to the log file. See Logger.SetFileLogging.
+func WithFileLogging(enabled bool) Option {
+	return func(l *Logger) {
+		l.noFile = !enabled
+	}
+}
+
+// WithLevel sets the initial log level.
+func WithLevel(level logrus.Level) Option {
+	return func(l *Logger) {
+		l.SetLevelL(level)
+	}
+}
+
+// WithConsole enables or disables writing to stdout in addition to the log file. It overrides LOG_TO_CONSOLE
+// environment variable.
+func WithConsole(enabled bool) Option {
+	return func(l *Logger) {
+		l.console = enabled
+	}
+}
+
+// WithMaxSizeMB sets the maximum size in megabytes of the log file before it gets rotated.
+func WithMaxSizeMB(size int) Option {
+	return func(l *Logger) {
+		l.rotation.MaxSize = size
+		l.sizeRotation = size != 0
+	}
+}
+
+// WithRotationConfig sets all rotation parameters of the log file.
+func WithRotationConfig(cfg RotationConfig) Option {
+	return func(l *Logger) {
+		l.rotation = cfg
+		l.sizeRotation = cfg.MaxSize != 0
+	}
+}
+
+// WithFormat sets the encoding of the log lines.
+func WithFormat(format Format) Option {
+	return func(l *Logger) {
+		l.formatter.setFormat(format)
+	}
+}
@@ -0,0 +1,47 @@
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIsolatedLoggers(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_new_*")
+	require.NoError(t, err)
+	defer func() {
+		os.RemoveAll(dir)
+	}()
+
+	auditPath := filepath.Join(dir, "audit.log")
+	debugPath := filepath.Join(dir, "debug.log")
+
+	audit := New(WithFilePath(auditPath), WithConsole(false))
+	debug := New(WithFilePath(debugPath), WithConsole(false), WithLevel(logrus.DebugLevel), WithMaxSizeMB(1))
+
+	require.Equal(t, logrus.InfoLevel, audit.GetLevel())
+	require.Equal(t, logrus.DebugLevel, debug.GetLevel())
+
+	auditMessage := randStringBytes(30)
+	debugMessage := randStringBytes(30)
+
+	audit.Debugf("%s", debugMessage)
+	audit.Infof("%s", auditMessage)
+	debug.Debugf("%s", debugMessage)
+
+	content, err := ioutil.ReadFile(auditPath)
+	require.NoError(t, err)
+	require.Contains(t, string(content), auditMessage)
+	require.NotContains(t, string(content), debugMessage)
+	require.Contains(t, string(content), "options_test.go:")
+
+	content, err = ioutil.ReadFile(debugPath)
+	require.NoError(t, err)
+	require.Contains(t, string(content), debugMessage)
+	require.NotContains(t, string(content), auditMessage)
+}
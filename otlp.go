@@ -0,0 +1,299 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	otlpLogsPath      = "/v1/logs"
+	otlpQueueSize     = 4096
+	otlpBatchSize     = 512
+	otlpBatchInterval = time.Second
+	otlpScopeName     = "github.com/binalyze/logger"
+)
+
+// otlpSeverities maps the levels to the severity numbers of the OpenTelemetry log data model.
+var otlpSeverities = map[logrus.Level]int{
+	logrus.TraceLevel: 1,
+	logrus.DebugLevel: 5,
+	logrus.InfoLevel:  9,
+	logrus.WarnLevel:  13,
+	logrus.ErrorLevel: 17,
+	logrus.FatalLevel: 21,
+	logrus.PanicLevel: 24,
+}
+
+// otlpCallerKeys maps the caller fields to the attributes of the OpenTelemetry semantic conventions.
+var otlpCallerKeys = map[string]string{
+	"file":     "code.filepath",
+	"line":     "code.lineno",
+	"function": "code.function",
+}
+
+// OTLPOption configures an exporter added by AddOTLPExporter.
+type OTLPOption func(*otlpHook)
+
+// WithOTLPHeader sets a header sent with every request, e.g. an authorization token of the collector.
+func WithOTLPHeader(key, value string) OTLPOption {
+	return func(h *otlpHook) {
+		h.sender.headers.Set(key, value)
+	}
+}
+
+// WithOTLPServiceName sets the service.name resource attribute, the name of the executable by default.
+func WithOTLPServiceName(name string) OTLPOption {
+	return func(h *otlpHook) {
+		h.serviceName = name
+	}
+}
+
+// otlpAttribute is a key value pair of an OTLP log record or resource.
+type otlpAttribute struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+// otlpRecord is a log record in the OTLP/JSON encoding.
+type otlpRecord struct {
+	TimeUnixNano         string          `json:"timeUnixNano"`
+	ObservedTimeUnixNano string          `json:"observedTimeUnixNano"`
+	SeverityNumber       int             `json:"severityNumber"`
+	SeverityText         string          `json:"severityText"`
+	Body                 otlpValue       `json:"body"`
+	Attributes           []otlpAttribute `json:"attributes,omitempty"`
+}
+
+// otlpValue is a string value in the OTLP/JSON encoding.
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+// otlpHook exports entries as OpenTelemetry log records over OTLP/HTTP with the JSON encoding in batches. Entries are
+// queued and exported by a single goroutine when a batch is full, the batch interval elapses or on flush, so that
+// logging never blocks on the network. Entries are dropped if the queue is full or all attempts fail.
+type otlpHook struct {
+	logger      *Logger
+	serviceName string
+	// sender posts the batches with the retry of the HTTP hook
+	sender    *httpHook
+	queue     chan otlpRecord
+	flushes   chan chan struct{}
+	batchSize int
+	interval  time.Duration
+	dropped   uint64
+}
+
+// AddOTLPExporter exports log lines as OpenTelemetry log records to the OTLP/HTTP endpoint of a collector, e.g.
+// http://collector:4318. The severity is mapped from the level, the body is the message and the attributes are the
+// custom fields with the caller as code.filepath, code.lineno and code.function. Records are exported in the
+// background in batches of up to 512 records at least every second, retrying server errors. Records are dropped if
+// the queue overflows or they can't be delivered. Flush and Close export the pending records, fatal and panic lines
+// are exported before returning. It returns an error if endpoint is not a valid http(s) URL.
+func (l *Logger) AddOTLPExporter(endpoint string, opts ...OTLPOption) error {
+	hook, err := newOTLPHook(l, endpoint, opts...)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	l.otlpHooks = append(l.otlpHooks, hook)
+	l.mu.Unlock()
+
+	l.AddHook(hook)
+	go hook.run()
+
+	return nil
+}
+
+// newOTLPHook creates a hook exporting entries of l to the logs endpoint of the collector at endpoint.
+func newOTLPHook(l *Logger, endpoint string, opts ...OTLPOption) (*otlpHook, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(u.Path, otlpLogsPath) {
+		u.Path = strings.TrimSuffix(u.Path, "/") + otlpLogsPath
+	}
+
+	sender, err := newHTTPHook(u.String(), logrus.TraceLevel, l)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &otlpHook{
+		logger:      l,
+		serviceName: getLogFileName(""),
+		sender:      sender,
+		queue:       make(chan otlpRecord, otlpQueueSize),
+		flushes:     make(chan chan struct{}),
+		batchSize:   otlpBatchSize,
+		interval:    otlpBatchInterval,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h, nil
+}
+
+// Levels returns the levels the hook fires for.
+func (h *otlpHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire queues entry to be exported, dropping it if the queue is full. Fatal and panic entries are exported before it
+// returns since the process is about to end.
+func (h *otlpHook) Fire(entry *logrus.Entry) error {
+	r := otlpRecord{
+		TimeUnixNano:         strconv.FormatInt(entry.Time.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityNumber:       otlpSeverities[entry.Level],
+		Attributes:           make([]otlpAttribute, 0, len(entry.Data)),
+	}
+
+	f := h.logger.formatter
+	f.mu.RLock()
+	r.SeverityText = f.levelName(entry.Level)
+	r.Body.StringValue = f.prefix + f.message(entry)
+	for _, key := range callerKeys {
+		if value, ok := entry.Data[key]; ok {
+			r.Attributes = append(r.Attributes, otlpAttribute{otlpCallerKeys[key], otlpAnyValue(value)})
+		}
+	}
+	for _, key := range customKeys(entry) {
+		r.Attributes = append(r.Attributes, otlpAttribute{key, otlpAnyValue(f.fieldValue(key, entry.Data[key]))})
+	}
+	f.mu.RUnlock()
+
+	select {
+	case h.queue <- r:
+	default:
+		atomic.AddUint64(&h.dropped, 1)
+	}
+
+	if entry.Level <= logrus.FatalLevel {
+		h.flush()
+	}
+
+	return nil
+}
+
+// otlpAnyValue returns value in the AnyValue encoding of OTLP/JSON, integers as strings like the protobuf JSON
+// mapping of int64.
+func otlpAnyValue(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case bool:
+		return map[string]interface{}{"boolValue": v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return map[string]interface{}{"intValue": fmt.Sprint(v)}
+	case float32:
+		return map[string]interface{}{"doubleValue": float64(v)}
+	case float64:
+		return map[string]interface{}{"doubleValue": v}
+	case error:
+		return map[string]interface{}{"stringValue": v.Error()}
+	default:
+		return map[string]interface{}{"stringValue": fmt.Sprint(v)}
+	}
+}
+
+// flush waits until the records queued so far are exported.
+func (h *otlpHook) flush() {
+	done := make(chan struct{})
+	h.flushes <- done
+	<-done
+}
+
+// run batches the queued records and exports the batches.
+func (h *otlpHook) run() {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	batch := make([]otlpRecord, 0, h.batchSize)
+	add := func(r otlpRecord) {
+		batch = append(batch, r)
+		if len(batch) >= h.batchSize {
+			h.export(batch)
+			batch = batch[:0]
+		}
+	}
+
+	for {
+		select {
+		case r := <-h.queue:
+			add(r)
+		case <-ticker.C:
+			if len(batch) > 0 {
+				h.export(batch)
+				batch = batch[:0]
+			}
+		case done := <-h.flushes:
+			for queued := true; queued; {
+				select {
+				case r := <-h.queue:
+					add(r)
+				default:
+					queued = false
+				}
+			}
+			if len(batch) > 0 {
+				h.export(batch)
+				batch = batch[:0]
+			}
+			close(done)
+		}
+	}
+}
+
+// export posts batch as a single scope of the resource, counting its records as dropped if it can't be delivered.
+func (h *otlpHook) export(batch []otlpRecord) {
+	resource := []otlpAttribute{{"service.name", map[string]interface{}{"stringValue": h.serviceName}}}
+
+	f := h.logger.formatter
+	f.mu.RLock()
+	version := f.appVersion()
+	f.mu.RUnlock()
+	if version != "" {
+		resource = append(resource, otlpAttribute{"service.version", map[string]interface{}{"stringValue": version}})
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"resourceLogs": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{"attributes": resource},
+				"scopeLogs": []interface{}{
+					map[string]interface{}{
+						"scope":      map[string]interface{}{"name": otlpScopeName},
+						"logRecords": batch,
+					},
+				},
+			},
+		},
+	})
+	if err == nil {
+		err = h.sender.post(payload)
+	}
+	if err != nil {
+		atomic.AddUint64(&h.dropped, uint64(len(batch)))
+	}
+}
+
+// flushOTLP exports the pending records of the OTLP exporters, callers must hold l.mu.
+func (l *Logger) flushOTLP() {
+	for _, hook := range l.otlpHooks {
+		hook.flush()
+	}
+}
+
+// AddOTLPExporter exports log lines of the default logger to an OpenTelemetry collector. See Logger.AddOTLPExporter.
+func AddOTLPExporter(endpoint string, opts ...OTLPOption) error {
+	return std.AddOTLPExporter(endpoint, opts...)
+}
@@ -0,0 +1,135 @@
+package logger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type otlpExport struct {
+	ResourceLogs []struct {
+		Resource struct {
+			Attributes []otlpAttribute `json:"attributes"`
+		} `json:"resource"`
+		ScopeLogs []struct {
+			Scope struct {
+				Name string `json:"name"`
+			} `json:"scope"`
+			LogRecords []otlpRecord `json:"logRecords"`
+		} `json:"scopeLogs"`
+	} `json:"resourceLogs"`
+}
+
+// attributes returns the attributes as a map of their values.
+func attributes(attrs []otlpAttribute) map[string]interface{} {
+	m := make(map[string]interface{}, len(attrs))
+	for _, attr := range attrs {
+		for _, value := range attr.Value {
+			m[attr.Key] = value
+		}
+	}
+
+	return m
+}
+
+// otlpCollector returns a mock collector recording the exported records.
+func otlpCollector(t *testing.T) (*httptest.Server, func() []otlpExport) {
+	var (
+		mu      sync.Mutex
+		exports []otlpExport
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/logs", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		var export otlpExport
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&export))
+		mu.Lock()
+		exports = append(exports, export)
+		mu.Unlock()
+	}))
+
+	return server, func() []otlpExport {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]otlpExport(nil), exports...)
+	}
+}
+
+func TestAddOTLPExporter(t *testing.T) {
+
+	server, exports := otlpCollector(t)
+	defer server.Close()
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+	l.SetVersion("2.0.0")
+	l.SetRedactedKeys("password")
+
+	err := l.AddOTLPExporter(server.URL, WithOTLPHeader("Authorization", "Bearer token"), WithOTLPServiceName("api"))
+	require.NoError(t, err)
+
+	l.WithFields(map[string]interface{}{"user": "alice", "attempt": 2, "password": "secret"}).Warn("login failed")
+	l.Infof("%s", "served")
+	require.NoError(t, l.Flush())
+
+	require.Len(t, exports(), 1)
+	export := exports()[0]
+	require.Len(t, export.ResourceLogs, 1)
+	require.Equal(t, map[string]interface{}{"service.name": "api", "service.version": "2.0.0"},
+		attributes(export.ResourceLogs[0].Resource.Attributes))
+	require.Equal(t, "github.com/binalyze/logger", export.ResourceLogs[0].ScopeLogs[0].Scope.Name)
+
+	records := export.ResourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(t, records, 2)
+	require.Equal(t, 13, records[0].SeverityNumber)
+	require.Equal(t, "WARNING", records[0].SeverityText)
+	require.Equal(t, "login failed", records[0].Body.StringValue)
+	require.NotEmpty(t, records[0].TimeUnixNano)
+	attrs := attributes(records[0].Attributes)
+	require.Equal(t, "alice", attrs["user"])
+	require.Equal(t, "2", attrs["attempt"])
+	require.Equal(t, "***", attrs["password"])
+	require.Contains(t, attrs["code.filepath"], "otlp_test.go")
+	require.Contains(t, attrs["code.function"], "TestAddOTLPExporter")
+	require.Equal(t, 9, records[1].SeverityNumber)
+}
+
+func TestOTLPExporterPanic(t *testing.T) {
+
+	server, exports := otlpCollector(t)
+	defer server.Close()
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+	require.NoError(t, l.AddOTLPExporter(server.URL+"/v1/logs", WithOTLPHeader("Authorization", "Bearer token")))
+
+	// The panic record is exported before panicking
+	require.Panics(t, func() {
+		l.Panicf("%s", "unrecoverable")
+	})
+	require.Len(t, exports(), 1)
+	records := exports()[0].ResourceLogs[0].ScopeLogs[0].LogRecords
+	require.Len(t, records, 1)
+	require.Equal(t, 24, records[0].SeverityNumber)
+	require.Equal(t, "unrecoverable", records[0].Body.StringValue)
+
+	_, err := newOTLPHook(l, "ftp://localhost:4318")
+	require.Error(t, err)
+
+	// Without os.Args the service is named after the default app name
+	defer func(args []string) {
+		os.Args = args
+	}(os.Args)
+	os.Args = nil
+	hook, err := newOTLPHook(l, "http://localhost:4318")
+	require.NoError(t, err)
+	require.Equal(t, defaultAppName, hook.serviceName)
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Print logs a message at level Info, args are handled as in fmt.Sprint like log.Print of the standard library.
+func (l *Logger) Print(args ...interface{}) {
+	l.logArgs(logrus.InfoLevel, args...)
+}
+
+// Printf logs a message at level Info, like log.Printf of the standard library.
+func (l *Logger) Printf(format string, args ...interface{}) {
+	l.logf(logrus.InfoLevel, format, args...)
+}
+
+// Println logs a message at level Info, args are handled as in fmt.Sprintln like log.Println of the standard library.
+// The trailing newline is dropped since the formatter terminates the line.
+func (l *Logger) Println(args ...interface{}) {
+	l.logln(logrus.InfoLevel, args...)
+}
+
+// logln logs args at level separated by spaces if level is enabled. Exported helpers must call it directly so that the
+// caller frame is always skipFrameCount frames away.
+func (l *Logger) logln(level logrus.Level, args ...interface{}) {
+	if !l.isLevelEnabled(level) {
+		return
+	}
+
+	if entry := l.newEntry(0, level, nil, true); entry != nil {
+		l.write(entry, level, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+	}
+}
+
+// Print logs a message at level Info on the standard logger. See Logger.Print.
+func Print(args ...interface{}) {
+	std.logArgs(logrus.InfoLevel, args...)
+}
+
+// Printf logs a message at level Info on the standard logger. See Logger.Printf.
+func Printf(format string, args ...interface{}) {
+	std.logf(logrus.InfoLevel, format, args...)
+}
+
+// Println logs a message at level Info on the standard logger. See Logger.Println.
+func Println(args ...interface{}) {
+	std.logln(logrus.InfoLevel, args...)
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrint(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.Print("a", "b", 1, 2)
+	require.True(t, strings.HasPrefix(buf.String(), "INFO "))
+	require.Contains(t, buf.String(), " ab1 2 file:")
+	require.Contains(t, buf.String(), "print_test.go:")
+
+	buf.Reset()
+	l.Printf("%s=%d", "a", 1)
+	require.Contains(t, buf.String(), " a=1 file:")
+
+	// Println separates all args by spaces without an extra newline
+	buf.Reset()
+	l.Println("a", "b", 1, 2)
+	require.Contains(t, buf.String(), " a b 1 2 file:")
+	require.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	require.Contains(t, buf.String(), "print_test.go:")
+
+	buf.Reset()
+	l.SetLevelL(logrus.WarnLevel)
+	l.Println("not logged")
+	require.Empty(t, buf.String())
+}
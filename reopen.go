@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"math"
+	"os"
+	"os/signal"
+)
+
+// NoSizeRotation is the RotationConfig.MaxSize which never rotates the log file, for files rotated by an external tool
+// like logrotate.
+const NoSizeRotation = math.MaxInt32
+
+// Reopen flushes the output and closes the log file and the files of routed levels, tags and audit lines, so that the
+// next write opens them again by path. It's meant for files rotated by an external tool like logrotate: once the tool
+// renamed a file, Reopen makes the logger create a fresh one instead of writing to the renamed file. Set
+// RotationConfig.MaxSize to NoSizeRotation so that the logger doesn't rotate the file itself. After a copytruncate
+// rotation it resets the file size the rotation is based on.
+func (l *Logger) Reopen() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err := l.flush()
+	if closeErr := l.closeFiles(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// EnableReopenSignal reopens the log files with Reopen each time the process receives sig, e.g. syscall.SIGUSR1 sent by
+// a postrotate script of logrotate. Errors are reported to the handler set by SetWriteErrorHandler. Signal handling is
+// process wide like for EnableSignalLevelToggle, so the same signal can't be used for both. It returns a function
+// which stops handling sig.
+func (l *Logger) EnableReopenSignal(sig os.Signal) func() {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, sig)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				if err := l.Reopen(); err != nil {
+					l.writeError(err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// Reopen closes the log files of the default logger so that they're opened again by path. See Logger.Reopen.
+func Reopen() error {
+	return std.Reopen()
+}
+
+// EnableReopenSignal reopens the log files of the default logger each time the process receives sig. See
+// Logger.EnableReopenSignal.
+func EnableReopenSignal(sig os.Signal) func() {
+	return std.EnableReopenSignal(sig)
+}
@@ -0,0 +1,88 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// renameLogFile logs a line, renames the log file like logrotate and logs another line still reaching the renamed
+// file.
+func renameLogFile(t *testing.T, l *Logger, path string) string {
+	l.Infof("%s", "before rotation")
+	rotated := path + ".1"
+	require.NoError(t, os.Rename(path, rotated))
+	l.Infof("%s", "after rename")
+
+	return rotated
+}
+
+func TestReopen(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_reopen_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	// The renamed file isn't noticed by the periodic check
+	defer func(interval time.Duration) {
+		fileCheckInterval = interval
+	}(fileCheckInterval)
+	fileCheckInterval = time.Hour
+
+	path := filepath.Join(dir, "app.log")
+	l := New(WithConsole(false), WithFilePath(path), WithRotationConfig(RotationConfig{MaxSize: NoSizeRotation}))
+	defer l.Close()
+
+	rotated := renameLogFile(t, l, path)
+	require.NoError(t, l.Reopen())
+	l.Infof("%s", "after reopen")
+
+	content, err := ioutil.ReadFile(rotated)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "before rotation")
+	require.Contains(t, string(content), "after rename")
+	require.NotContains(t, string(content), "after reopen")
+
+	content, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(content), "\n"))
+	require.Contains(t, string(content), "after reopen")
+}
+
+func TestEnableReopenSignal(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_reopen_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer func(interval time.Duration) {
+		fileCheckInterval = interval
+	}(fileCheckInterval)
+	fileCheckInterval = time.Hour
+
+	path := filepath.Join(dir, "app.log")
+	l := New(WithConsole(false), WithFilePath(path))
+	defer l.Close()
+
+	stop := l.EnableReopenSignal(syscall.SIGUSR2)
+	defer stop()
+
+	renameLogFile(t, l, path)
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR2))
+
+	// The fresh file is created by the first write after the signal was handled
+	require.Eventually(t, func() bool {
+		l.Infof("%s", "after reopen")
+		content, err := ioutil.ReadFile(path)
+		return err == nil && strings.Contains(string(content), "after reopen")
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ringBuffer keeps the last lines logged, formatted the same as the log file.
+type ringBuffer struct {
+	logger *Logger
+
+	mu    sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+// Levels returns the levels the hook fires for.
+func (r *ringBuffer) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire formats entry and stores it in place of the oldest line once the ring is full.
+func (r *ringBuffer) Fire(entry *logrus.Entry) error {
+	line, err := r.logger.formatter.formatLine(entry)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.lines) == 0 {
+		return nil
+	}
+
+	r.lines[r.next] = strings.TrimRight(string(line), "\r\n")
+	r.next = (r.next + 1) % len(r.lines)
+	if r.next == 0 {
+		r.full = true
+	}
+
+	return nil
+}
+
+// resize drops the stored lines and makes the ring hold n lines.
+func (r *ringBuffer) resize(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lines = make([]string, n)
+	r.next = 0
+	r.full = false
+}
+
+// snapshot returns the stored lines from the oldest to the newest.
+func (r *ringBuffer) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]string(nil), r.lines[:r.next]...)
+	}
+
+	return append(append(make([]string, 0, len(r.lines)), r.lines[r.next:]...), r.lines[:r.next]...)
+}
+
+// EnableRingBuffer keeps the last n lines in memory in addition to the regular output, e.g. to serve recent logs from
+// a diagnostics endpoint. The lines are formatted the same as the log file without the line separator. Calling it
+// again drops the stored lines, n <= 0 disables it.
+func (l *Logger) EnableRingBuffer(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ring == nil {
+		if n == 0 {
+			return
+		}
+		l.ring = &ringBuffer{logger: l}
+		l.AddHook(l.ring)
+	}
+
+	l.ring.resize(n)
+}
+
+// RingBufferSnapshot returns the lines kept by EnableRingBuffer from the oldest to the newest, nil if it's disabled.
+func (l *Logger) RingBufferSnapshot() []string {
+	l.mu.Lock()
+	ring := l.ring
+	l.mu.Unlock()
+
+	if ring == nil {
+		return nil
+	}
+
+	return ring.snapshot()
+}
+
+// EnableRingBuffer keeps the last n lines of the default logger in memory. See Logger.EnableRingBuffer.
+func EnableRingBuffer(n int) {
+	std.EnableRingBuffer(n)
+}
+
+// RingBufferSnapshot returns the lines kept in memory by the default logger. See Logger.RingBufferSnapshot.
+func RingBufferSnapshot() []string {
+	return std.RingBufferSnapshot()
+}
@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRingBuffer(t *testing.T) {
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+	require.Nil(t, l.RingBufferSnapshot())
+
+	l.EnableRingBuffer(3)
+	l.Infof("%s", "line 0")
+	require.Len(t, l.RingBufferSnapshot(), 1)
+
+	for i := 1; i < 10; i++ {
+		l.Infof("line %d", i)
+	}
+
+	lines := l.RingBufferSnapshot()
+	require.Len(t, lines, 3)
+	for i, line := range lines {
+		require.True(t, strings.HasPrefix(line, "INFO "))
+		require.Contains(t, line, fmt.Sprintf(" line %d file:", 7+i))
+		require.NotContains(t, line, "\n")
+	}
+
+	// Resizing drops the stored lines
+	l.EnableRingBuffer(2)
+	require.Empty(t, l.RingBufferSnapshot())
+
+	l.EnableRingBuffer(0)
+	l.Infof("%s", "not kept")
+	require.Empty(t, l.RingBufferSnapshot())
+}
+
+func TestRingBufferConcurrent(t *testing.T) {
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+	l.EnableRingBuffer(16)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Infof("line %d", j)
+				l.RingBufferSnapshot()
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, l.RingBufferSnapshot(), 16)
+}
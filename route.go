@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// routeHook writes entries at or above a level to a separate rotated file, in addition to the logger's output.
+type routeHook struct {
+	logger *Logger
+	levels []logrus.Level
+	file   *lumberjack.Logger
+}
+
+// Levels returns the levels the hook fires for.
+func (h *routeHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire writes entry to the file, formatted the same as the log file.
+func (h *routeHook) Fire(entry *logrus.Entry) error {
+	line, err := h.logger.formatter.formatLine(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = h.file.Write(line)
+	return err
+}
+
+// RouteLevel writes log lines at or above minLevel to the rotated file at path with rotation cfg too, e.g. to keep
+// errors in a separate file. The log file still receives all levels. Close closes the file. It returns an error if
+// the file can't be created.
+func (l *Logger) RouteLevel(minLevel logrus.Level, path string, cfg RotationConfig) error {
+	if err := checkLogFile(path); err != nil {
+		return err
+	}
+
+	hook := &routeHook{
+		logger: l,
+		levels: levelsFrom(minLevel),
+		file: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    cfg.MaxSize,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAge,
+			Compress:   cfg.Compress,
+		},
+	}
+
+	l.mu.Lock()
+	l.routes = append(l.routes, hook)
+	l.mu.Unlock()
+
+	l.AddHook(hook)
+
+	return nil
+}
+
+// SetErrorFile writes log lines at or above level Error to the file at path too, rotated like the log file. See
+// Logger.RouteLevel.
+func (l *Logger) SetErrorFile(path string) error {
+	l.mu.Lock()
+	cfg := l.rotation
+	l.mu.Unlock()
+
+	return l.RouteLevel(logrus.ErrorLevel, path, cfg)
+}
+
+// mirrorHook writes the entries of a single level to a writer, in addition to the logger's output.
+type mirrorHook struct {
+	logger *Logger
+	level  logrus.Level
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// Levels returns the mirrored level.
+func (h *mirrorHook) Levels() []logrus.Level {
+	return []logrus.Level{h.level}
+}
+
+// Fire writes entry to the writer, formatted the same as the log file.
+func (h *mirrorHook) Fire(entry *logrus.Entry) error {
+	line, err := h.logger.formatter.formatLine(entry)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, err = h.w.Write(line)
+	return err
+}
+
+// MirrorLevel writes log lines of exactly level to w too, formatted like the log file, e.g. to capture the Debug lines
+// in a transient file which can be deleted freely. Unlike RouteLevel other levels aren't mirrored. The lines still
+// reach the log file and the level must be enabled for them to be logged at all. Writes to w are serialized, w is not
+// closed by Close.
+func (l *Logger) MirrorLevel(level logrus.Level, w io.Writer) {
+	l.AddHook(&mirrorHook{logger: l, level: level, w: w})
+}
+
+// closeRoutes closes the files of the routed levels, callers must hold l.mu. Like the log file they're reopened lazily
+// on the next write.
+func (l *Logger) closeRoutes() error {
+	var err error
+	for _, hook := range l.routes {
+		if closeErr := hook.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// RouteLevel writes log lines of the default logger at or above minLevel to the file at path too. See
+// Logger.RouteLevel.
+func RouteLevel(minLevel logrus.Level, path string, cfg RotationConfig) error {
+	return std.RouteLevel(minLevel, path, cfg)
+}
+
+// MirrorLevel writes log lines of the default logger of exactly level to w too. See Logger.MirrorLevel.
+func MirrorLevel(level logrus.Level, w io.Writer) {
+	std.MirrorLevel(level, w)
+}
+
+// SetErrorFile writes log lines of the default logger at or above level Error to the file at path too. See
+// Logger.SetErrorFile.
+func SetErrorFile(path string) error {
+	return std.SetErrorFile(path)
+}
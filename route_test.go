@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetErrorFile(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "route")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	mainPath := filepath.Join(dir, "app.log")
+	errorPath := filepath.Join(dir, "app.error.log")
+
+	l := New(WithConsole(false), WithFilePath(mainPath))
+	require.NoError(t, l.SetErrorFile(errorPath))
+
+	l.Infof("%s", "request served")
+	l.Errorf("%s", "request failed")
+	require.NoError(t, l.Close())
+
+	mainData, err := ioutil.ReadFile(mainPath)
+	require.NoError(t, err)
+	errorData, err := ioutil.ReadFile(errorPath)
+	require.NoError(t, err)
+
+	mainLines := strings.Split(strings.TrimSpace(string(mainData)), "\n")
+	require.Len(t, mainLines, 2)
+	require.Contains(t, mainLines[0], "request served")
+
+	// The error file gets the same bytes as the main file
+	require.Equal(t, mainLines[1]+"\n", string(errorData))
+	require.Contains(t, string(errorData), "request failed")
+}
+
+func TestRouteLevel(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "route")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l := New(WithConsole(false), WithFilePath(filepath.Join(dir, "app.log")))
+	warnPath := filepath.Join(dir, "app.warn.log")
+	require.NoError(t, l.RouteLevel(logrus.WarnLevel, warnPath, RotationConfig{MaxSize: 1}))
+	require.Error(t, l.RouteLevel(logrus.WarnLevel, dir, RotationConfig{}))
+
+	l.Infof("%s", "info")
+	l.Warnf("%s", "warn")
+	l.Errorf("%s", "error")
+	require.NoError(t, l.Close())
+
+	data, err := ioutil.ReadFile(warnPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "info")
+	require.Contains(t, string(data), "warn")
+	require.Contains(t, string(data), "error")
+}
+
+func TestMirrorLevel(t *testing.T) {
+
+	var out, mirror bytes.Buffer
+	l := New(WithConsole(false), WithLevel(logrus.DebugLevel))
+	l.SetOutput(&out)
+	l.MirrorLevel(logrus.DebugLevel, &mirror)
+
+	l.Debugf("%s", "cache miss")
+	l.Infof("%s", "request served")
+	l.Warnf("%s", "slow request")
+	l.Tracef("%s", "not enabled")
+
+	lines := strings.Split(strings.TrimSuffix(mirror.String(), "\n"), "\n")
+	require.Len(t, lines, 1)
+	require.Contains(t, lines[0], "DEBUG ")
+	require.Contains(t, lines[0], "cache miss")
+
+	// The log output still receives all enabled levels
+	require.Contains(t, out.String(), "cache miss")
+	require.Contains(t, out.String(), "request served")
+	require.Contains(t, out.String(), "slow request")
+}
@@ -0,0 +1,75 @@
+package logger
+
+import (
+	"runtime/debug"
+	"time"
+)
+
+// SafeGoOption configures a goroutine started by SafeGo.
+type SafeGoOption func(*safeGoConfig)
+
+type safeGoConfig struct {
+	repanic bool
+}
+
+// WithRepanic panics again with the same value after logging the panic, which crashes the process like an unrecovered
+// panic but makes sure the panic is in the log first.
+func WithRepanic() SafeGoOption {
+	return func(c *safeGoConfig) {
+		c.repanic = true
+	}
+}
+
+// SafeGo runs fn in a goroutine which recovers from a panic of fn and logs it at level Error with the stack trace in
+// the stack field, so that goroutines don't die silently.
+func (l *Logger) SafeGo(fn func(), opts ...SafeGoOption) {
+	var cfg safeGoConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	go func() {
+		if value, panicked := l.runRecovered(fn); panicked && cfg.repanic {
+			panic(value)
+		}
+	}()
+}
+
+// SafeGoRestart runs fn in a goroutine like SafeGo, restarting it after delay whenever it panics until it returns
+// normally, e.g. for long-running workers.
+func (l *Logger) SafeGoRestart(fn func(), delay time.Duration) {
+	go func() {
+		for {
+			if _, panicked := l.runRecovered(fn); !panicked {
+				return
+			}
+			time.Sleep(delay)
+		}
+	}()
+}
+
+// runRecovered runs fn and returns the value it panicked with, logging the panic with its stack trace.
+func (l *Logger) runRecovered(fn func()) (value interface{}, panicked bool) {
+	defer func() {
+		if value = recover(); value != nil {
+			panicked = true
+			l.WithFields(map[string]interface{}{"stack": string(debug.Stack())}).NoCaller().
+				Errorf("recovered panic: %v", value)
+			_ = l.Flush()
+		}
+	}()
+
+	fn()
+	return nil, false
+}
+
+// SafeGo runs fn in a goroutine logging its panic on the default logger. See Logger.SafeGo.
+func SafeGo(fn func(), opts ...SafeGoOption) {
+	std.SafeGo(fn, opts...)
+}
+
+// SafeGoRestart runs fn in a goroutine restarting it after a panic logged on the default logger. See
+// Logger.SafeGoRestart.
+func SafeGoRestart(fn func(), delay time.Duration) {
+	std.SafeGoRestart(fn, delay)
+}
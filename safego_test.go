@@ -0,0 +1,67 @@
+package logger
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafeGo(t *testing.T) {
+
+	var buf syncBuffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	done := make(chan struct{})
+	l.SafeGo(func() {
+		defer close(done)
+		panic("worker failed")
+	})
+	<-done
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(buf.String(), "recovered panic: worker failed")
+	}, 5*time.Second, 10*time.Millisecond)
+	require.True(t, strings.HasPrefix(buf.String(), "ERROR "))
+	require.Contains(t, buf.String(), "stack=")
+	require.Contains(t, buf.String(), "safego_test.go")
+}
+
+func TestSafeGoRestart(t *testing.T) {
+
+	var buf syncBuffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	var runs int32
+	done := make(chan struct{})
+	l.SafeGoRestart(func() {
+		if atomic.AddInt32(&runs, 1) < 3 {
+			panic("worker failed")
+		}
+		close(done)
+	}, time.Millisecond)
+	<-done
+
+	require.Equal(t, int32(3), atomic.LoadInt32(&runs))
+	require.Equal(t, 2, strings.Count(buf.String(), "recovered panic: worker failed"))
+}
+
+func TestRunRecovered(t *testing.T) {
+
+	var buf syncBuffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	_, panicked := l.runRecovered(func() {})
+	require.False(t, panicked)
+	require.Empty(t, buf.String())
+
+	value, panicked := l.runRecovered(func() { panic(42) })
+	require.True(t, panicked)
+	require.Equal(t, 42, value)
+	require.Contains(t, buf.String(), "recovered panic: 42")
+}
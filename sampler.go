@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// sampler limits the number of messages per key in fixed windows.
+type sampler struct {
+	n   int
+	per time.Duration
+
+	mu    sync.Mutex
+	sites map[string]*sampledSite
+}
+
+// sampledSite is the state of the current window of a key.
+type sampledSite struct {
+	start   time.Time
+	count   int
+	dropped int
+}
+
+// newSampler creates a sampler allowing n messages per key in each window of duration per.
+func newSampler(n int, per time.Duration) *sampler {
+	return &sampler{
+		n:     n,
+		per:   per,
+		sites: make(map[string]*sampledSite),
+	}
+}
+
+// allow reports whether a message with key is allowed at now. The first allowed message of a window reports the
+// number of messages dropped in the previous one.
+func (s *sampler) allow(key string, now time.Time) (allowed bool, dropped int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	site, ok := s.sites[key]
+	if !ok {
+		site = &sampledSite{start: now}
+		s.sites[key] = site
+	}
+
+	if now.Sub(site.start) >= s.per {
+		dropped = site.dropped
+		site.start = now
+		site.count = 0
+		site.dropped = 0
+	}
+
+	if site.count >= s.n {
+		site.dropped++
+		return false, 0
+	}
+	site.count++
+
+	return true, dropped
+}
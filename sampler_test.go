@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplerAllow(t *testing.T) {
+
+	s := newSampler(2, time.Second)
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		allowed, dropped := s.allow("main.go:10", now)
+		require.True(t, allowed)
+		require.Zero(t, dropped)
+	}
+	for i := 0; i < 3; i++ {
+		allowed, _ := s.allow("main.go:10", now.Add(time.Millisecond))
+		require.False(t, allowed)
+	}
+
+	// Other call sites have their own limit
+	allowed, _ := s.allow("main.go:20", now)
+	require.True(t, allowed)
+
+	// Next window reports the dropped messages once
+	allowed, dropped := s.allow("main.go:10", now.Add(time.Second))
+	require.True(t, allowed)
+	require.Equal(t, 3, dropped)
+
+	allowed, dropped = s.allow("main.go:10", now.Add(time.Second))
+	require.True(t, allowed)
+	require.Zero(t, dropped)
+}
+
+func TestSetSampling(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetSampling(2, 100*time.Millisecond)
+
+	retry := func() {
+		l.Errorf("%s", "retrying")
+	}
+
+	for i := 0; i < 5; i++ {
+		retry()
+	}
+	l.Errorf("%s", "other call site")
+	require.Equal(t, 3, strings.Count(buf.String(), "\n"))
+
+	time.Sleep(100 * time.Millisecond)
+	buf.Reset()
+	for i := 0; i < 5; i++ {
+		retry()
+	}
+	require.Contains(t, buf.String(), " dropped=3\n")
+
+	// Disabled sampling logs everything
+	buf.Reset()
+	l.SetSampling(0, 0)
+	for i := 0; i < 5; i++ {
+		retry()
+	}
+	require.Equal(t, 5, strings.Count(buf.String(), "\n"))
+}
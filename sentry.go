@@ -0,0 +1,121 @@
+//go:build sentry
+// +build sentry
+
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+)
+
+// sentryFlushTimeout bounds how long fatal and panic events are waited for before the process dies.
+const sentryFlushTimeout = 2 * time.Second
+
+// sentryHook captures entries as Sentry events.
+type sentryHook struct {
+	logger *Logger
+	client *sentry.Client
+	levels []logrus.Level
+}
+
+// AddSentryHook sends log lines at or above minLevel to Sentry as events, in addition to the logger's output. The
+// custom fields except the stack are attached as tags, the caller as the "caller" context and the stack trace of the error attached by
+// WithError, or of the logging goroutine, as the exception. Events are sent in the background, fatal and panic events
+// are flushed before Fatalf exits and Panicf panics. It's only available when built with the sentry build tag and
+// returns an error if dsn is invalid.
+func (l *Logger) AddSentryHook(dsn string, minLevel logrus.Level) error {
+	return l.addSentryHook(sentry.ClientOptions{Dsn: dsn}, minLevel)
+}
+
+// addSentryHook adds a hook capturing entries at or above minLevel with a Sentry client created with opts.
+func (l *Logger) addSentryHook(opts sentry.ClientOptions, minLevel logrus.Level) error {
+	client, err := sentry.NewClient(opts)
+	if err != nil {
+		return err
+	}
+
+	l.AddHook(&sentryHook{logger: l, client: client, levels: levelsFrom(minLevel)})
+
+	return nil
+}
+
+// Levels returns the levels the hook fires for.
+func (h *sentryHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire captures entry as an event, waiting for it to be sent if the process is about to die.
+func (h *sentryHook) Fire(entry *logrus.Entry) error {
+	event := sentry.NewEvent()
+	event.Level = sentryLevel(entry.Level)
+	event.Timestamp = entry.Time
+
+	f := h.logger.formatter
+	f.mu.RLock()
+	event.Message = f.prefix + f.message(entry)
+	for _, key := range customKeys(entry) {
+		// The stack trace is attached to the exception
+		if key == "stack" {
+			continue
+		}
+		value := f.fieldValue(key, entry.Data[key])
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		event.Tags[key] = fmt.Sprint(value)
+	}
+	f.mu.RUnlock()
+
+	caller := map[string]interface{}{}
+	for _, key := range callerKeys {
+		if value, ok := entry.Data[key]; ok {
+			caller[key] = value
+		}
+	}
+	if len(caller) > 0 {
+		event.Contexts["caller"] = caller
+	}
+
+	exception := sentry.Exception{Type: event.Message, Value: event.Message}
+	if err, ok := entry.Data["error"].(error); ok {
+		exception.Type = reflect.TypeOf(err).String()
+		exception.Value = err.Error()
+		exception.Stacktrace = sentry.ExtractStacktrace(err)
+	}
+	if exception.Stacktrace == nil {
+		exception.Stacktrace = sentry.NewStacktrace()
+	}
+	event.Exception = []sentry.Exception{exception}
+
+	h.client.CaptureEvent(event, nil, nil)
+	if entry.Level <= logrus.FatalLevel {
+		h.client.Flush(sentryFlushTimeout)
+	}
+
+	return nil
+}
+
+// sentryLevel maps level to the Sentry level, panic to fatal and trace to debug.
+func sentryLevel(level logrus.Level) sentry.Level {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return sentry.LevelFatal
+	case logrus.ErrorLevel:
+		return sentry.LevelError
+	case logrus.WarnLevel:
+		return sentry.LevelWarning
+	case logrus.InfoLevel:
+		return sentry.LevelInfo
+	default:
+		return sentry.LevelDebug
+	}
+}
+
+// AddSentryHook sends log lines of the default logger at or above minLevel to Sentry. See Logger.AddSentryHook.
+func AddSentryHook(dsn string, minLevel logrus.Level) error {
+	return std.AddSentryHook(dsn, minLevel)
+}
@@ -0,0 +1,73 @@
+//go:build sentry
+// +build sentry
+
+package logger
+
+import (
+	"errors"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// mockTransport records the events sent and the flushes.
+type mockTransport struct {
+	mu      sync.Mutex
+	events  []*sentry.Event
+	flushes int
+}
+
+func (t *mockTransport) Configure(sentry.ClientOptions) {}
+
+func (t *mockTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.events = append(t.events, event)
+}
+
+func (t *mockTransport) Flush(time.Duration) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.flushes++
+	return true
+}
+
+func TestSentryHook(t *testing.T) {
+
+	l := New(WithConsole(false))
+	l.SetOutput(ioutil.Discard)
+	l.log.ExitFunc = func(int) {}
+
+	transport := &mockTransport{}
+	require.NoError(t, l.addSentryHook(sentry.ClientOptions{Transport: transport}, logrus.ErrorLevel))
+
+	l.Infof("%s", "request served")
+	l.WithFields(map[string]interface{}{"request_id": 42}).WithError(errors.New("timeout")).
+		Errorf("%s", "request failed")
+
+	require.Len(t, transport.events, 1)
+	event := transport.events[0]
+	require.Equal(t, sentry.LevelError, event.Level)
+	require.Equal(t, "request failed", event.Message)
+	require.Equal(t, "42", event.Tags["request_id"])
+	require.Equal(t, "timeout", event.Tags["error"])
+	require.Equal(t, "timeout", event.Exception[0].Value)
+	require.NotNil(t, event.Exception[0].Stacktrace)
+	require.Contains(t, event.Contexts["caller"], "file")
+	require.Zero(t, transport.flushes)
+
+	// Fatal events are flushed before exiting
+	l.Fatalf("%s", "shutting down")
+	require.Len(t, transport.events, 2)
+	require.Equal(t, sentry.LevelFatal, transport.events[1].Level)
+	require.Equal(t, 1, transport.flushes)
+
+	require.Error(t, l.AddSentryHook("not a dsn", logrus.ErrorLevel))
+}
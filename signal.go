@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EnableSignalLevelToggle toggles debug logging each time the process receives sig, e.g. syscall.SIGUSR1, switching
+// from Info to Debug and from Debug or Trace back to Info. The change is logged at Info level like SetDebugLogging.
+// Signal handling is process wide: sig is also delivered to other handlers registered with signal.Notify, so don't use
+// a signal the application already handles, e.g. SIGHUP for configuration reloads. It returns a function which stops
+// handling sig.
+func (l *Logger) EnableSignalLevelToggle(sig os.Signal) func() {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, sig)
+
+	go func() {
+		for {
+			select {
+			case <-signals:
+				l.SetDebugLogging(!l.log.IsLevelEnabled(logrus.DebugLevel))
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// EnableSignalLevelToggle toggles debug logging of the default logger each time the process receives sig. See
+// Logger.EnableSignalLevelToggle.
+func EnableSignalLevelToggle(sig os.Signal) func() {
+	return std.EnableSignalLevelToggle(sig)
+}
@@ -0,0 +1,42 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logger
+
+import (
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnableSignalLevelToggle(t *testing.T) {
+
+	var buf syncBuffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	stop := l.EnableSignalLevelToggle(syscall.SIGUSR1)
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	require.Eventually(t, func() bool {
+		return l.GetLevel() == logrus.DebugLevel
+	}, 5*time.Second, time.Millisecond)
+	require.Contains(t, buf.String(), "Debug logging set to: true")
+
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	require.Eventually(t, func() bool {
+		return l.GetLevel() == logrus.InfoLevel
+	}, 5*time.Second, time.Millisecond)
+
+	// Trace is toggled back to Info too
+	l.SetLevelL(logrus.TraceLevel)
+	require.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	require.Eventually(t, func() bool {
+		return l.GetLevel() == logrus.InfoLevel
+	}, 5*time.Second, time.Millisecond)
+
+	stop()
+}
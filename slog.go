@@ -0,0 +1,114 @@
+//go:build go1.21
+// +build go1.21
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sirupsen/logrus"
+)
+
+// slogHandler is a slog.Handler logging the records with the logger's formatting and output.
+type slogHandler struct {
+	logger *Logger
+	// fields are the attributes added by WithAttrs, group is the dotted prefix of the keys added by WithGroup
+	fields logrus.Fields
+	group  string
+}
+
+// Enabled reports whether the logger's level enables level.
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.isLevelEnabled(slogLevel(level))
+}
+
+// Handle logs r with the fields extracted from ctx, the handler's attributes and the record's attributes, later ones
+// overriding earlier ones on key collision.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	level := slogLevel(r.Level)
+	if !h.logger.isLevelEnabled(level) {
+		return nil
+	}
+
+	fields := copyFields(h.logger.contextFields(ctx), h.fields)
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(fields, h.group, a)
+		return true
+	})
+
+	// The caller is the one recorded by slog, a zero PC means it's unknown
+	if entry := h.logger.newEntryAt(0, r.PC, level, fields, r.PC != 0); entry != nil {
+		entry.Time = r.Time
+		h.logger.write(entry, level, r.Message)
+	}
+
+	return nil
+}
+
+// WithAttrs returns a handler attaching attrs to its records.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := copyFields(h.fields, nil)
+	for _, a := range attrs {
+		addAttr(fields, h.group, a)
+	}
+
+	return &slogHandler{logger: h.logger, fields: fields, group: h.group}
+}
+
+// WithGroup returns a handler prefixing the keys of the subsequent attributes with name and a dot.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &slogHandler{logger: h.logger, fields: h.fields, group: h.group + name + "."}
+}
+
+// addAttr adds a to fields with its key prefixed by group. Group attributes are flattened into dotted keys, empty
+// attributes are ignored.
+func addAttr(fields logrus.Fields, group string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			group += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			addAttr(fields, group, ga)
+		}
+		return
+	}
+
+	fields[group+a.Key] = a.Value.Any()
+}
+
+// slogLevel maps level to the logrus level it falls in, levels below slog.LevelDebug map to logrus.TraceLevel.
+func slogLevel(level slog.Level) logrus.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return logrus.TraceLevel
+	case level < slog.LevelInfo:
+		return logrus.DebugLevel
+	case level < slog.LevelWarn:
+		return logrus.InfoLevel
+	case level < slog.LevelError:
+		return logrus.WarnLevel
+	default:
+		return logrus.ErrorLevel
+	}
+}
+
+// SlogHandler returns a slog.Handler logging the records with the logger's formatting and output, e.g. for
+// slog.New(l.SlogHandler()). Groups are rendered as dotted key prefixes.
+func (l *Logger) SlogHandler() slog.Handler {
+	return &slogHandler{logger: l, fields: logrus.Fields{}}
+}
+
+// SlogHandler returns a slog.Handler logging the records with the default logger. See Logger.SlogHandler.
+func SlogHandler() slog.Handler {
+	return std.SlogHandler()
+}
@@ -0,0 +1,111 @@
+//go:build go1.21
+// +build go1.21
+
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+// withoutTime removes the timestamp and the line number from a text log line.
+func withoutTime(line string) string {
+	parts := strings.SplitN(line, " ", 3)
+	line = parts[0] + " " + parts[2]
+
+	start := strings.Index(line, "slog_test.go:") + len("slog_test.go:")
+	end := start + strings.Index(line[start:], " ")
+
+	return line[:start] + line[end:]
+}
+
+func TestSlogHandler(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	logger := slog.New(l.SlogHandler())
+
+	logger.Info("user logged in", "user", "alice")
+	l.WithFields(map[string]interface{}{"user": "alice"}).Infof("%s", "user logged in")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[0], "func:.TestSlogHandler")
+	require.Equal(t, withoutTime(lines[1]), withoutTime(lines[0]))
+
+	// Levels map to the level they fall in and respect the logger's level
+	buf.Reset()
+	logger.Debug("not logged")
+	logger.Warn("disk almost full")
+	logger.Log(context.Background(), slog.LevelError+4, "critical")
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.True(t, strings.HasPrefix(lines[0], "WARNING "))
+	require.True(t, strings.HasPrefix(lines[1], "ERROR "))
+}
+
+func TestSlogHandlerAttrs(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetFormat(FormatJSON)
+
+	logger := slog.New(l.SlogHandler()).With("service", "api").WithGroup("req")
+	logger.Error("request failed",
+		"method", "GET",
+		slog.Group("client", "ip", "10.0.0.1"),
+		slog.Group("", "inline", true),
+		"err", errors.New("timeout"),
+	)
+
+	line := buf.String()
+	require.Contains(t, line, `"message":"request failed"`)
+	require.Contains(t, line, `"service":"api"`)
+	require.Contains(t, line, `"req.method":"GET"`)
+	require.Contains(t, line, `"req.client.ip":"10.0.0.1"`)
+	require.Contains(t, line, `"req.inline":true`)
+	require.Contains(t, line, `"req.err":"timeout"`)
+}
+
+// logWithCaller logs msg through h as a slog wrapper would, reporting the caller of logWithCaller.
+func logWithCaller(h slog.Handler, msg string) {
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, msg, pcs[0])
+	_ = h.Handle(context.Background(), r)
+}
+
+func TestSlogHandlerCaller(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+	l.SetAutoStackTrace(logrus.InfoLevel)
+	h := l.SlogHandler()
+
+	// The caller is the one recorded in the record regardless of the frames in between
+	_, _, line, _ := runtime.Caller(0)
+	logWithCaller(h, "wrapped")
+	require.Contains(t, buf.String(), "slog_test.go:"+strconv.Itoa(line+1)+" func:.TestSlogHandlerCaller")
+	require.True(t, strings.HasPrefix(buf.String()[strings.Index(buf.String(), "stack="):], `stack="`+
+		"github.com/binalyze/logger.TestSlogHandlerCaller"))
+
+	// A record without a PC has no caller
+	buf.Reset()
+	l.DisableAutoStackTrace()
+	_ = h.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelInfo, "unknown", 0))
+	require.Contains(t, buf.String(), "unknown")
+	require.NotContains(t, buf.String(), "func:")
+}
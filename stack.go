@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxStackDepth is the number of frames captured by automatic stack traces
+const maxStackDepth = 32
+
+// SetAutoStackTrace attaches the stack trace of the logging goroutine under the stack field to the entries at or above
+// minLevel, e.g. logrus.ErrorLevel, so that it doesn't have to be attached by hand. The trace starts at the caller of
+// the logger and lists each function with its file and line. A stack trace attached by WithError takes precedence.
+// Capturing the stack is expensive, it's disabled by default and can be disabled again with DisableAutoStackTrace.
+func (l *Logger) SetAutoStackTrace(minLevel logrus.Level) {
+	// Stored off by one so that zero disables it
+	atomic.StoreInt32(&l.autoStackLevel, int32(minLevel)+1)
+}
+
+// DisableAutoStackTrace stops attaching the stack trace enabled by SetAutoStackTrace.
+func (l *Logger) DisableAutoStackTrace() {
+	atomic.StoreInt32(&l.autoStackLevel, 0)
+}
+
+// autoStackTrace reports whether the stack trace is attached to entries at level.
+func (l *Logger) autoStackTrace(level logrus.Level) bool {
+	return int32(level) < atomic.LoadInt32(&l.autoStackLevel)
+}
+
+// callerStack returns the stack trace starting skip frames above runtime.Callers, or at the frame of the program
+// counter from if it's on the stack, formatted as the function followed by its file and line indented on the next line
+// for each frame.
+func callerStack(skip int, from uintptr, prefixes []string) string {
+	pc := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(skip, pc)
+	pc = pc[:n]
+	for i := range pc {
+		if from != 0 && pc[i] == from {
+			pc = pc[i:]
+			break
+		}
+	}
+	frames := runtime.CallersFrames(pc)
+
+	var sb strings.Builder
+	for {
+		frame, more := frames.Next()
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(frame.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(callerPath(frame.File, frame.Function, prefixes))
+		sb.WriteString(":")
+		sb.WriteString(strconv.Itoa(frame.Line))
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
+
+// SetAutoStackTrace attaches the stack trace to the entries of the default logger at or above minLevel. See
+// Logger.SetAutoStackTrace.
+func SetAutoStackTrace(minLevel logrus.Level) {
+	std.SetAutoStackTrace(minLevel)
+}
+
+// DisableAutoStackTrace stops attaching the stack trace to the entries of the default logger. See
+// Logger.DisableAutoStackTrace.
+func DisableAutoStackTrace() {
+	std.DisableAutoStackTrace()
+}
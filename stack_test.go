@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetAutoStackTrace(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false), WithFormat(FormatJSON))
+	l.SetOutput(&buf)
+	l.SetAutoStackTrace(logrus.ErrorLevel)
+
+	decode := func() map[string]interface{} {
+		var decoded map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		buf.Reset()
+		return decoded
+	}
+
+	l.Errorf("%s", "failed")
+	stack, ok := decode()["stack"].(string)
+	require.True(t, ok)
+	// The trace starts at the caller of the logger
+	require.True(t, strings.HasPrefix(stack, "github.com/binalyze/logger.TestSetAutoStackTrace\n\t"), stack)
+	require.Contains(t, stack, "stack_test.go:")
+	require.NotContains(t, stack, "newEntry")
+
+	l.Infof("%s", "served")
+	require.NotContains(t, decode(), "stack")
+
+	// It's attached without the caller too
+	l.NoCaller().Warnf("%s", "ignored")
+	require.NotContains(t, decode(), "stack")
+	l.NoCaller().Errorf("%s", "failed")
+	require.Contains(t, decode(), "stack")
+
+	// A stack attached already, e.g. by WithError, takes precedence
+	l.WithFields(map[string]interface{}{"stack": "custom"}).Error("failed")
+	require.Equal(t, "custom", decode()["stack"])
+
+	l.DisableAutoStackTrace()
+	l.WithError(errors.New("failed")).Error("failed")
+	require.NotContains(t, decode(), "stack")
+}
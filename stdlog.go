@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"log"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// stdLogDepth is the number of frames of the log package between the caller and stdLogWriter.Write, e.g.
+// log.(*Logger).Printf and log.(*Logger).output.
+const stdLogDepth = 1
+
+// stdLogWriter re-emits the lines written by a standard library logger at level.
+type stdLogWriter struct {
+	logger *Logger
+	level  logrus.Level
+}
+
+// Write logs p as a message at level, without the trailing newline added by the log package.
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	if !w.logger.isLevelEnabled(w.level) {
+		return len(p), nil
+	}
+
+	if entry := w.logger.newEntry(stdLogDepth, w.level, nil, true); entry != nil {
+		w.logger.write(entry, w.level, strings.TrimSuffix(string(p), "\n"))
+	}
+
+	return len(p), nil
+}
+
+// StdLogger returns a standard library logger whose lines are logged at level with the logger's formatting and
+// output, e.g. for http.Server.ErrorLog. The caller reported is the one of the standard library logger.
+func (l *Logger) StdLogger(level logrus.Level) *log.Logger {
+	return log.New(&stdLogWriter{logger: l, level: level}, "", 0)
+}
+
+// StdLogger returns a standard library logger whose lines are logged by the default logger at level. See
+// Logger.StdLogger.
+func StdLogger(level logrus.Level) *log.Logger {
+	return std.StdLogger(level)
+}
@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdLogger(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false))
+	l.SetOutput(&buf)
+
+	l.StdLogger(logrus.WarnLevel).Printf("http: TLS handshake error from %s", "127.0.0.1:5000")
+	l.StdLogger(logrus.InfoLevel).Println("connection closed")
+	l.StdLogger(logrus.DebugLevel).Print("not logged")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	require.True(t, strings.HasPrefix(lines[0], "WARNING "))
+	require.Contains(t, lines[0], " http: TLS handshake error from 127.0.0.1:5000 file:")
+	require.Contains(t, lines[0], "stdlog_test.go:")
+	require.Contains(t, lines[0], "func:.TestStdLogger")
+	require.True(t, strings.HasPrefix(lines[1], "INFO "))
+	require.Contains(t, lines[1], " connection closed file:")
+}
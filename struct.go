@@ -0,0 +1,130 @@
+package logger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// structTag is the tag naming the field of a struct field logged by InfoStruct, "-" skips it
+	structTag = "log"
+
+	// maxStructDepth limits the nesting of the structs flattened by InfoStruct, which also stops cyclic pointers
+	maxStructDepth = 5
+
+	// maxStructDepthValue replaces the structs nested deeper than maxStructDepth
+	maxStructDepthValue = "[max depth]"
+)
+
+// InfoStruct logs msg at level Info with the exported fields of the struct v as fields, e.g. to log a configuration
+// or a request without listing its fields. The fields are named by their log tag, e.g. `log:"user_id"`, or by their
+// Go name, fields tagged `log:"-"` and fields whose name is redacted by SetRedactedKeys are skipped. Nested structs are
+// flattened with dotted names, e.g. db.host, up to 5 levels deep, which also stops cyclic pointers, the structs nested
+// deeper are logged as "[max depth]". Exported embedded structs are flattened without their name. Structs implementing
+// fmt.Stringer or error, like time.Time, are logged as values. A v which isn't a struct or a pointer to one is logged
+// in the value field.
+func (l *Logger) InfoStruct(msg string, v interface{}) {
+	l.logStruct(logrus.InfoLevel, msg, v)
+}
+
+// logStruct logs msg with the fields of the struct v if level is enabled. Exported helpers must call it directly so
+// that the caller frame is always skipFrameCount frames away.
+func (l *Logger) logStruct(level logrus.Level, msg string, v interface{}) {
+	if !l.isLevelEnabled(level) {
+		return
+	}
+
+	if entry := l.newEntry(0, level, l.structFields(v), true); entry != nil {
+		l.write(entry, level, msg)
+	}
+}
+
+// structFields returns the fields of the struct v, or v in the value field if it isn't a struct.
+func (l *Logger) structFields(v interface{}) logrus.Fields {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct || isStructValue(value) {
+		return logrus.Fields{"value": v}
+	}
+
+	fields := make(logrus.Fields, value.NumField())
+	l.addStructFields(fields, "", value, 1)
+
+	return fields
+}
+
+// addStructFields adds the exported fields of the struct value to fields, their names prefixed with prefix. depth is
+// the nesting level of value.
+func (l *Logger) addStructFields(fields logrus.Fields, prefix string, value reflect.Value, depth int) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		if tag := strings.Split(field.Tag.Get(structTag), ",")[0]; tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+		if l.formatter.isRedacted(name) || l.formatter.isRedacted(prefix+name) {
+			continue
+		}
+
+		fieldValue := value.Field(i)
+		for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() {
+			fieldValue = fieldValue.Elem()
+		}
+
+		// Embedded structs without a tag are flattened in place like encoding/json does
+		nested := fieldValue.Kind() == reflect.Struct && !isStructValue(fieldValue)
+		if field.Anonymous && nested && field.Tag.Get(structTag) == "" {
+			if depth < maxStructDepth {
+				l.addStructFields(fields, prefix, fieldValue, depth+1)
+			}
+			continue
+		}
+
+		switch {
+		case !nested:
+			fields[prefix+name] = fieldValue.Interface()
+		case depth < maxStructDepth:
+			l.addStructFields(fields, prefix+name+".", fieldValue, depth+1)
+		default:
+			fields[prefix+name] = maxStructDepthValue
+		}
+	}
+}
+
+// isStructValue reports whether the struct value is logged as a single value since it renders itself, e.g. time.Time.
+func isStructValue(value reflect.Value) bool {
+	if !value.CanInterface() {
+		return false
+	}
+
+	switch value.Interface().(type) {
+	case fmt.Stringer, error:
+		return true
+	}
+	if value.CanAddr() {
+		switch value.Addr().Interface().(type) {
+		case fmt.Stringer, error:
+			return true
+		}
+	}
+
+	return false
+}
+
+// InfoStruct logs msg at level Info on the standard logger with the exported fields of the struct v as fields. See
+// Logger.InfoStruct.
+func InfoStruct(msg string, v interface{}) {
+	std.logStruct(logrus.InfoLevel, msg, v)
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Audited is embedded by structLoginRequest.
+type Audited struct {
+	CreatedBy string `log:"created_by"`
+}
+
+type structDatabase struct {
+	Host string
+	Port int
+}
+
+type structLoginRequest struct {
+	Audited
+	UserID   string `log:"user_id"`
+	Password string `log:"-"`
+	Token    string
+	Attempts *int
+	Started  time.Time
+	DB       *structDatabase `log:"db"`
+	internal string
+}
+
+// structNode points to itself.
+type structNode struct {
+	Name string
+	Next *structNode
+}
+
+func TestInfoStruct(t *testing.T) {
+
+	l := New(WithConsole(false))
+	logs, restore := l.CaptureForTest()
+	defer restore()
+	l.SetRedactedKeys("token")
+
+	attempts := 2
+	started := time.Date(2024, 1, 23, 10, 0, 0, 0, time.UTC)
+	l.InfoStruct("login", &structLoginRequest{
+		Audited:  Audited{CreatedBy: "admin"},
+		UserID:   "42",
+		Password: "hunter2",
+		Token:    "secret",
+		Attempts: &attempts,
+		Started:  started,
+		DB:       &structDatabase{Host: "db.local", Port: 5432},
+		internal: "internal",
+	})
+
+	entry := logs.Last()
+	require.Equal(t, "login", entry.Message)
+	require.Contains(t, entry.Fields["file"], "struct_test.go")
+	delete(entry.Fields, "file")
+	delete(entry.Fields, "line")
+	delete(entry.Fields, "function")
+	require.Equal(t, map[string]interface{}{
+		"created_by": "admin",
+		"user_id":    "42",
+		"Attempts":   2,
+		"Started":    started,
+		"db.Host":    "db.local",
+		"db.Port":    5432,
+	}, entry.Fields)
+
+	// Cyclic structures stop at the depth limit
+	node := &structNode{Name: "a"}
+	node.Next = node
+	l.InfoStruct("cycle", node)
+	require.Equal(t, "a", logs.Last().Fields["Next.Next.Next.Next.Name"])
+	require.Equal(t, maxStructDepthValue, logs.Last().Fields["Next.Next.Next.Next.Next"])
+
+	// Values which aren't structs are logged as is
+	l.InfoStruct("value", 42)
+	require.Equal(t, 42, logs.Last().Fields["value"])
+}
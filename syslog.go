@@ -0,0 +1,29 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logger
+
+import (
+	"log/syslog"
+
+	lsyslog "github.com/sirupsen/logrus/hooks/syslog"
+)
+
+// AddSyslogHook forwards log lines to the syslog daemon at addr over network, e.g. "udp" and "localhost:514", in
+// addition to the logger's output. Levels are mapped to syslog severities, ERROR to LOG_ERR, WARNING to LOG_WARNING
+// and so on. An empty network connects to the local syslog daemon. It returns an error if the connection fails.
+func (l *Logger) AddSyslogHook(network, addr, tag string) error {
+	hook, err := lsyslog.NewSyslogHook(network, addr, syslog.LOG_USER|syslog.LOG_INFO, tag)
+	if err != nil {
+		return err
+	}
+
+	l.AddHook(hook)
+
+	return nil
+}
+
+// AddSyslogHook forwards log lines of the default logger to the syslog daemon. See Logger.AddSyslogHook.
+func AddSyslogHook(network, addr, tag string) error {
+	return std.AddSyslogHook(network, addr, tag)
+}
@@ -0,0 +1,18 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package logger
+
+import (
+	"errors"
+)
+
+// AddSyslogHook is not supported on this platform and always returns an error.
+func (l *Logger) AddSyslogHook(network, addr, tag string) error {
+	return errors.New("syslog is not supported on this platform")
+}
+
+// AddSyslogHook is not supported on this platform and always returns an error.
+func AddSyslogHook(network, addr, tag string) error {
+	return std.AddSyslogHook(network, addr, tag)
+}
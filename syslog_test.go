@@ -0,0 +1,67 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package logger
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddSyslogHook(t *testing.T) {
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	l := New(WithConsole(false))
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+
+	err = l.AddSyslogHook("udp", conn.LocalAddr().String(), "logger_test")
+	require.NoError(t, err)
+
+	read := func() string {
+		packet := make([]byte, 4096)
+		err := conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		require.NoError(t, err)
+		n, _, err := conn.ReadFrom(packet)
+		require.NoError(t, err)
+		return string(packet[:n])
+	}
+
+	messageError := randStringBytes(30)
+	l.Errorf("%s", messageError)
+
+	// LOG_USER|LOG_ERR
+	packet := read()
+	require.True(t, strings.HasPrefix(packet, "<11>"), packet)
+	require.Contains(t, packet, "logger_test")
+	require.Contains(t, packet, messageError)
+	require.Contains(t, buf.String(), messageError)
+
+	// Hook survives output changes
+	var other bytes.Buffer
+	l.SetOutput(&other)
+
+	messageWarning := randStringBytes(30)
+	l.Warnf("%s", messageWarning)
+
+	// LOG_USER|LOG_WARNING
+	packet = read()
+	require.True(t, strings.HasPrefix(packet, "<12>"), packet)
+	require.Contains(t, packet, messageWarning)
+	require.Contains(t, other.String(), messageWarning)
+}
+
+func TestAddSyslogHookError(t *testing.T) {
+
+	l := New(WithConsole(false))
+	err := l.AddSyslogHook("tcp", "127.0.0.1:1", "logger_test")
+	require.Error(t, err)
+}
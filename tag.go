@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// tagKey is the field holding the tag of the entries logged through Tag
+	tagKey = "tag"
+
+	// maxTagFiles limits the files opened by Tag so that a tag per request can't exhaust the file descriptors
+	maxTagFiles = 64
+)
+
+// tagName is the value of the tag field, typed so that the formatter can tell tagged entries from a custom field
+// named tag.
+type tagName string
+
+// tagHook writes tagged entries to the file of their tag, the log file skips them.
+type tagHook struct {
+	logger *Logger
+
+	mu    sync.Mutex
+	files map[tagName]io.WriteCloser
+}
+
+// Tag returns an Entry whose lines are written to a separate file of the category name instead of the log file, e.g.
+// app.billing.log next to app.log for Tag("billing"). The file is created on first use with the rotation of the log
+// file and the lines carry the tag field so that the files can be merged later. Console output and hooks receive the
+// tagged lines as usual. At most 64 tags can be used, it returns an error beyond that, if name is not a valid file
+// name component or if the file can't be created. Close closes the files.
+func (l *Logger) Tag(name string) (*Entry, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid tag %q", name)
+	}
+
+	l.mu.Lock()
+	if l.tags == nil {
+		l.tags = &tagHook{logger: l, files: map[tagName]io.WriteCloser{}}
+		l.AddHook(l.tags)
+	}
+	hook := l.tags
+	path := tagFilePath(l.filePath, name)
+	rotation := l.rotation
+	daily := l.daily
+	l.mu.Unlock()
+
+	if err := hook.open(tagName(name), path, rotation, daily); err != nil {
+		return nil, err
+	}
+
+	return &Entry{logger: l, fields: logrus.Fields{tagKey: tagName(name)}}, nil
+}
+
+// tagFilePath returns the path of the file of tag next to the log file at path, e.g. app.billing.log for app.log.
+func tagFilePath(path, tag string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + tag + ext
+}
+
+// open creates the file of tag at path unless it's open already.
+func (h *tagHook) open(tag tagName, path string, rotation RotationConfig, daily bool) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.files[tag]; ok {
+		return nil
+	}
+	if len(h.files) >= maxTagFiles {
+		return fmt.Errorf("too many tags, at most %d tag files can be open", maxTagFiles)
+	}
+
+	file, err := openSideFile(path, rotation, daily)
+	if err != nil {
+		return err
+	}
+	h.files[tag] = file
+
+	return nil
+}
+
+// openSideFile returns a file at path rotated like the log file, by day if daily is set and with rotation otherwise,
+// e.g. for the files of tags. It returns an error if the file can't be created.
+func openSideFile(path string, rotation RotationConfig, daily bool) (io.WriteCloser, error) {
+	if daily {
+		if err := checkLogFile(dailyFileName(path, time.Now())); err != nil {
+			return nil, err
+		}
+		return newDailyFile(path, rotation.MaxAge, time.Now), nil
+	}
+
+	if err := checkLogFile(path); err != nil {
+		return nil, err
+	}
+
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    rotation.MaxSize,
+		MaxBackups: rotation.MaxBackups,
+		MaxAge:     rotation.MaxAge,
+		Compress:   rotation.Compress,
+	}, nil
+}
+
+// Levels returns the levels the hook fires for.
+func (h *tagHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire writes entry to the file of its tag if it's tagged.
+func (h *tagHook) Fire(entry *logrus.Entry) error {
+	tag, ok := entry.Data[tagKey].(tagName)
+	if !ok {
+		return nil
+	}
+
+	h.mu.Lock()
+	file := h.files[tag]
+	h.mu.Unlock()
+	if file == nil {
+		return nil
+	}
+
+	line, err := h.logger.formatter.formatLine(entry)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(line)
+	return err
+}
+
+// close closes the tag files. Like the log file they're reopened lazily on the next write.
+func (h *tagHook) close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var err error
+	for _, file := range h.files {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
+}
+
+// Tag returns an Entry of the default logger writing to the file of the category name. See Logger.Tag.
+func Tag(name string) (*Entry, error) {
+	return std.Tag(name)
+}
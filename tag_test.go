@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTag(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_tag_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l := New(WithConsole(false), WithFilePath(filepath.Join(dir, "app.log")))
+	defer l.Close()
+	l.SetAsync(16)
+
+	billing, err := l.Tag("billing")
+	require.NoError(t, err)
+	billing.WithFields(map[string]interface{}{"invoice": 42}).Infof("charged")
+	l.Infof("untagged")
+	require.NoError(t, l.Flush())
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "app.billing.log"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "charged")
+	require.Contains(t, string(content), "tag=billing")
+	require.Contains(t, string(content), "invoice=42")
+	require.NotContains(t, string(content), "untagged")
+
+	content, err = ioutil.ReadFile(filepath.Join(dir, "app.log"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "untagged")
+	require.NotContains(t, string(content), "charged")
+
+	// A custom field named tag doesn't route the line
+	l.WithFields(map[string]interface{}{"tag": "billing"}).Infof("custom tag")
+	require.NoError(t, l.Flush())
+	content, err = ioutil.ReadFile(filepath.Join(dir, "app.log"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "custom tag")
+}
+
+func TestTagJSON(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_tag_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l := New(WithConsole(false), WithFilePath(filepath.Join(dir, "app.log")), WithFormat(FormatJSON))
+	defer l.Close()
+
+	audit, err := l.Tag("audit")
+	require.NoError(t, err)
+	audit.Infof("logged in")
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "app.audit.log"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), `"tag":"audit"`)
+}
+
+func TestTagLimit(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_tag_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	l := New(WithConsole(false), WithFilePath(filepath.Join(dir, "app.log")))
+	defer l.Close()
+
+	for _, name := range []string{"", "../billing", `a\b`, ".", ".."} {
+		_, err = l.Tag(name)
+		require.Error(t, err, name)
+	}
+
+	for i := 0; i < maxTagFiles; i++ {
+		_, err := l.Tag(fmt.Sprintf("tag%d", i))
+		require.NoError(t, err)
+	}
+
+	// Tags in use can still be retrieved
+	_, err = l.Tag("tag0")
+	require.NoError(t, err)
+
+	_, err = l.Tag("one-too-many")
+	require.EqualError(t, err, "too many tags, at most 64 tag files can be open")
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OperationTimer measures the duration of an operation and logs it when stopped.
+type OperationTimer struct {
+	logger *Logger
+	name   string
+	start  time.Time
+}
+
+// Timer starts timing the operation name, e.g. t := logger.Timer("db.query"); defer t.Stop().
+func (l *Logger) Timer(name string) *OperationTimer {
+	return &OperationTimer{logger: l, name: name, start: time.Now()}
+}
+
+// Stop logs "<name> took <duration>" at level Info with the elapsed milliseconds in the numeric duration_ms field and
+// returns the elapsed duration.
+func (t *OperationTimer) Stop() time.Duration {
+	return t.stop(logrus.InfoLevel, nil)
+}
+
+// StopWith logs like Stop at level with fields attached, e.g. the number of rows of a query.
+func (t *OperationTimer) StopWith(level logrus.Level, fields map[string]interface{}) time.Duration {
+	return t.stop(level, fields)
+}
+
+// stop logs the elapsed duration at level with fields if level is enabled. Exported helpers must call it directly so
+// that the caller frame is always skipFrameCount frames away.
+func (t *OperationTimer) stop(level logrus.Level, fields map[string]interface{}) time.Duration {
+	elapsed := time.Since(t.start)
+	if !t.logger.isLevelEnabled(level) {
+		return elapsed
+	}
+
+	entryFields := copyFields(nil, fields)
+	entryFields["duration_ms"] = float64(elapsed) / float64(time.Millisecond)
+	if entry := t.logger.newEntry(0, level, entryFields, true); entry != nil {
+		t.logger.write(entry, level, t.name+" took "+elapsed.String())
+	}
+
+	return elapsed
+}
+
+// Timer starts timing the operation name on the default logger. See Logger.Timer.
+func Timer(name string) *OperationTimer {
+	return std.Timer(name)
+}
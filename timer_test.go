@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimer(t *testing.T) {
+
+	var buf bytes.Buffer
+	l := New(WithConsole(false), WithFormat(FormatJSON))
+	l.SetOutput(&buf)
+
+	timer := l.Timer("db.query")
+	time.Sleep(10 * time.Millisecond)
+	elapsed := timer.Stop()
+	require.GreaterOrEqual(t, int64(elapsed), int64(10*time.Millisecond))
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Equal(t, "db.query took "+elapsed.String(), decoded["message"])
+	require.IsType(t, float64(0), decoded["duration_ms"])
+	require.InDelta(t, float64(elapsed)/float64(time.Millisecond), decoded["duration_ms"], 0.001)
+	require.Contains(t, decoded["file"], "timer_test.go")
+
+	// Level and fields are set on stop
+	buf.Reset()
+	l.SetFormat(FormatText)
+	l.Timer("db.query").StopWith(logrus.WarnLevel, map[string]interface{}{"rows": 42})
+	line := strings.TrimSpace(buf.String())
+	require.True(t, strings.HasPrefix(line, "WARNING "))
+	require.Contains(t, line, " duration_ms=")
+	require.True(t, strings.HasSuffix(line, " rows=42"))
+
+	buf.Reset()
+	l.Timer("db.query").StopWith(logrus.DebugLevel, nil)
+	require.Empty(t, buf.String())
+}
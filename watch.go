@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileCheckInterval is how often the log file is checked to still exist, a variable so that tests can check on every
+// write.
+var fileCheckInterval = time.Second
+
+// watchedFile writes to the log file, recreating it if it's removed, e.g. by a cleanup tool or an unmount of its
+// directory, and reporting the writes which still fail to the write error handler.
+type watchedFile struct {
+	// Writer writes to the file, possibly through a compressor
+	io.Writer
+	// file is closed to reopen the file on the next write, which recreates its directory
+	file io.Closer
+	// name returns the path of the file currently written
+	name   func() string
+	logger *Logger
+
+	mu      sync.Mutex
+	checked time.Time
+}
+
+// Write writes p to the file. Writes to a removed file would succeed without reaching any file, so the file is checked
+// at most once every fileCheckInterval and reopened if it can't be found. A failed write is retried once with the file
+// reopened, writing only the part of p which wasn't written yet, the error of the retry is reported to the handler set
+// by SetWriteErrorHandler.
+func (f *watchedFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if now := time.Now(); now.Sub(f.checked) >= fileCheckInterval {
+		f.checked = now
+		if _, err := os.Stat(f.name()); err != nil {
+			_ = f.file.Close()
+		}
+	}
+
+	n, err := f.Writer.Write(p)
+	if err != nil {
+		_ = f.file.Close()
+		// Only the rest is retried so that a short write doesn't duplicate the start of the line
+		var m int
+		m, err = f.Writer.Write(p[n:])
+		n += m
+		if err != nil {
+			f.logger.writeError(err)
+		}
+	}
+
+	return n, err
+}
+
+// SetWriteErrorHandler sets fn to be called with the error of writes to the log file which fail even after the file
+// was reopened, e.g. because its directory can't be recreated, so that the application can raise an alarm or fall
+// back to stderr. Removed files and directories are recreated without calling fn. fn is called while the line is being
+// written and must not log through the logger. A nil fn removes the handler, the failures are only reported to the
+// handler set by SetInternalErrorHandler then.
+func (l *Logger) SetWriteErrorHandler(fn func(error)) {
+	l.writeErrorHandler.Store(fn)
+}
+
+// writeError reports err to the write error handler if there is one.
+func (l *Logger) writeError(err error) {
+	if fn, _ := l.writeErrorHandler.Load().(func(error)); fn != nil {
+		fn(err)
+	}
+}
+
+// SetWriteErrorHandler sets the handler of failed writes to the default logger's log file. See
+// Logger.SetWriteErrorHandler.
+func SetWriteErrorHandler(fn func(error)) {
+	std.SetWriteErrorHandler(fn)
+}
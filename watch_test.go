@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchedFileRecreated(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_watch_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer func(interval time.Duration) {
+		fileCheckInterval = interval
+	}(fileCheckInterval)
+	fileCheckInterval = 0
+
+	path := filepath.Join(dir, "logs", "app.log")
+	l := New(WithConsole(false), WithFilePath(path))
+	defer l.Close()
+
+	var errs []error
+	l.SetWriteErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	l.Infof("before removal")
+	require.NoError(t, os.RemoveAll(filepath.Dir(path)))
+	l.Infof("after removal")
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(content), "after removal")
+	require.NotContains(t, string(content), "before removal")
+	require.Empty(t, errs)
+}
+
+func TestSetWriteErrorHandler(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_watch_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	defer func(interval time.Duration) {
+		fileCheckInterval = interval
+	}(fileCheckInterval)
+	fileCheckInterval = 0
+
+	path := filepath.Join(dir, "logs", "app.log")
+	l := New(WithConsole(false), WithFilePath(path))
+	defer l.Close()
+
+	var errs []error
+	l.SetWriteErrorHandler(func(err error) {
+		errs = append(errs, err)
+	})
+
+	l.Infof("before removal")
+	require.Empty(t, errs)
+
+	// The directory can't be recreated once a file takes its place
+	require.NoError(t, os.RemoveAll(filepath.Dir(path)))
+	require.NoError(t, ioutil.WriteFile(filepath.Dir(path), nil, 0644))
+	l.Infof("after removal")
+	require.Len(t, errs, 1)
+
+	l.SetWriteErrorHandler(nil)
+	l.Infof("without handler")
+	require.Len(t, errs, 1)
+}
+
+// shortWriter writes half of the first line it's given and fails, then writes normally.
+type shortWriter struct {
+	bytes.Buffer
+	failed bool
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if !w.failed {
+		w.failed = true
+		n, _ := w.Buffer.Write(p[:len(p)/2])
+		return n, errors.New("short write")
+	}
+
+	return w.Buffer.Write(p)
+}
+
+// nopCloser is an io.Closer doing nothing.
+type nopCloser struct{}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func TestWatchedFileShortWrite(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "_logger_watch_*")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var w shortWriter
+	l := New(WithConsole(false), WithFileLogging(false))
+	f := &watchedFile{Writer: &w, file: nopCloser{}, name: func() string { return dir }, logger: l}
+
+	// The retry writes the rest of the line only
+	n, err := f.Write([]byte("message\n"))
+	require.NoError(t, err)
+	require.Equal(t, 8, n)
+	require.Equal(t, "message\n", w.String())
+}